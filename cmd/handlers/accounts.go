@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/application"
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+)
+
+type createAccountRequest struct {
+	Name           string          `json:"name"`
+	Currency       string          `json:"currency"`
+	Metadata       json.RawMessage `json:"metadata"`
+	BalancePolicy  string          `json:"balance_policy"`
+	OverdraftLimit int64           `json:"overdraft_limit"`
+	AccountClass   string          `json:"account_class"`
+	ParentID       uuid.UUID       `json:"parent_id"`
+}
+
+type accountParentRequest struct {
+	ParentID uuid.UUID `json:"parent_id"`
+}
+
+type balancePolicyRequest struct {
+	BalancePolicy  string `json:"balance_policy"`
+	OverdraftLimit int64  `json:"overdraft_limit"`
+}
+
+type accountResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	Name           string          `json:"name"`
+	Currency       string          `json:"currency"`
+	Status         string          `json:"status"`
+	AccountClass   string          `json:"account_class"`
+	NormalBalance  string          `json:"normal_balance"`
+	ParentID       *uuid.UUID      `json:"parent_id,omitempty"`
+	BalancePolicy  string          `json:"balance_policy"`
+	OverdraftLimit int64           `json:"overdraft_limit,omitempty"`
+	Metadata       json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ClosedAt       *time.Time      `json:"closed_at,omitempty"`
+}
+
+func newAccountResponse(account repo.Account) accountResponse {
+	response := accountResponse{
+		ID:             account.ID,
+		Name:           account.Name,
+		Currency:       string(account.Currency),
+		Status:         account.Status,
+		AccountClass:   string(account.AccountClass),
+		NormalBalance:  account.NormalBalance,
+		BalancePolicy:  account.BalancePolicy,
+		OverdraftLimit: account.OverdraftLimit,
+		Metadata:       account.Metadata,
+		CreatedAt:      account.CreatedAt.Time,
+	}
+	if account.ClosedAt.Valid {
+		response.ClosedAt = &account.ClosedAt.Time
+	}
+	if account.ParentID.Valid {
+		response.ParentID = &account.ParentID.UUID
+	}
+
+	return response
+}
+
+func (h *LedgerHandler) GetAccountsHandler(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.ledgerService.GetAllAccounts(r.Context())
+	if err != nil {
+		httputils.RespondError(w, http.StatusInternalServerError, httputils.InternalSrvErrMsg)
+		return
+	}
+
+	response := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		response[i] = newAccountResponse(account)
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
+
+func (h *LedgerHandler) CreateAccountHandler(w http.ResponseWriter, r *http.Request) {
+	var request createAccountRequest
+	err := httputils.DecodeJSON(w, r, &request)
+	if err != nil {
+		return
+	}
+
+	account, err := h.ledgerService.CreateAccount(r.Context(), &domain.NewAccount{
+		Name:           request.Name,
+		Currency:       request.Currency,
+		Metadata:       request.Metadata,
+		BalancePolicy:  request.BalancePolicy,
+		OverdraftLimit: request.OverdraftLimit,
+		Class:          request.AccountClass,
+		ParentID:       request.ParentID,
+	})
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusCreated, newAccountResponse(account))
+}
+
+func (h *LedgerHandler) GetAccountHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+
+	account, err := h.ledgerService.GetAccount(r.Context(), id)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newAccountResponse(account))
+}
+
+func (h *LedgerHandler) UpdateAccountMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+
+	var patch json.RawMessage
+	err := httputils.DecodeJSON(w, r, &patch)
+	if err != nil {
+		return
+	}
+
+	account, err := h.ledgerService.UpdateAccountMetadata(r.Context(), id, patch)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newAccountResponse(account))
+}
+
+func (h *LedgerHandler) UpdateBalancePolicyHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+
+	var request balancePolicyRequest
+	err := httputils.DecodeJSON(w, r, &request)
+	if err != nil {
+		return
+	}
+
+	account, err := h.ledgerService.UpdateBalancePolicy(r.Context(), id, request.BalancePolicy, request.OverdraftLimit)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newAccountResponse(account))
+}
+
+func (h *LedgerHandler) UpdateAccountParentHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+
+	var request accountParentRequest
+	err := httputils.DecodeJSON(w, r, &request)
+	if err != nil {
+		return
+	}
+
+	account, err := h.ledgerService.SetAccountParent(r.Context(), id, request.ParentID)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newAccountResponse(account))
+}
+
+func (h *LedgerHandler) CloseAccountHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+
+	account, err := h.ledgerService.CloseAccount(r.Context(), id)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newAccountResponse(account))
+}
+
+func accountID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "invalid account id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondAccountError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrInvalidAccount),
+		errors.Is(err, application.ErrInvalidCursor):
+		httputils.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, application.ErrAccountNotFound):
+		httputils.RespondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, application.ErrAccountClosed),
+		errors.Is(err, application.ErrAccountHasBalance):
+		httputils.RespondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, application.ErrAccountBusy):
+		httputils.RespondError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		httputils.RespondError(w, http.StatusInternalServerError, httputils.InternalSrvErrMsg)
+	}
+}
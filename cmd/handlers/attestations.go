@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/application"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/google/uuid"
+)
+
+type attestationResponse struct {
+	Day           string    `json:"day"`
+	Root          string    `json:"root"`
+	LeafCount     int64     `json:"leaf_count"`
+	FirstChainSeq int64     `json:"first_chain_seq"`
+	LastChainSeq  int64     `json:"last_chain_seq"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type proofStepResponse struct {
+	Hash     string `json:"hash"`
+	Position string `json:"position"`
+}
+
+type inclusionProofResponse struct {
+	TransactionID uuid.UUID           `json:"transaction_id"`
+	Day           string              `json:"day"`
+	Root          string              `json:"root"`
+	LeafCount     int64               `json:"leaf_count"`
+	Index         int                 `json:"index"`
+	LeafData      json.RawMessage     `json:"leaf_data"`
+	Leaf          string              `json:"leaf"`
+	Path          []proofStepResponse `json:"path"`
+}
+
+func (h *LedgerHandler) GetAttestationHandler(w http.ResponseWriter, r *http.Request) {
+	day, err := time.Parse(time.DateOnly, r.PathValue("day"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "day must be formatted as YYYY-MM-DD")
+		return
+	}
+
+	attestation, err := h.ledgerService.GetAttestation(r.Context(), day)
+	if err != nil {
+		respondAttestationError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, attestationResponse{
+		Day:           attestation.Day.Format(time.DateOnly),
+		Root:          hex.EncodeToString(attestation.Root),
+		LeafCount:     attestation.LeafCount,
+		FirstChainSeq: attestation.FirstChainSeq,
+		LastChainSeq:  attestation.LastChainSeq,
+		CreatedAt:     attestation.CreatedAt,
+	})
+}
+
+func (h *LedgerHandler) GetTransactionProofHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "invalid transaction id")
+		return
+	}
+
+	proof, err := h.ledgerService.GetInclusionProof(r.Context(), id)
+	if err != nil {
+		respondAttestationError(w, err)
+		return
+	}
+
+	response := inclusionProofResponse{
+		TransactionID: proof.TransactionID,
+		Day:           proof.Day.Format(time.DateOnly),
+		Root:          hex.EncodeToString(proof.Root),
+		LeafCount:     proof.LeafCount,
+		Index:         proof.Index,
+		LeafData:      proof.LeafData,
+		Leaf:          hex.EncodeToString(proof.Leaf),
+		Path:          make([]proofStepResponse, len(proof.Path)),
+	}
+	for i, step := range proof.Path {
+		position := "right"
+		if step.Left {
+			position = "left"
+		}
+		response.Path[i] = proofStepResponse{Hash: hex.EncodeToString(step.Hash), Position: position}
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
+
+func respondAttestationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrTransactionNotFound),
+		errors.Is(err, application.ErrAttestationNotFound):
+		httputils.RespondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, application.ErrProofUnavailable),
+		errors.Is(err, application.ErrAttestationMismatch):
+		httputils.RespondError(w, http.StatusConflict, err.Error())
+	default:
+		httputils.RespondError(w, http.StatusInternalServerError, httputils.InternalSrvErrMsg)
+	}
+}
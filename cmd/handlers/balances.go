@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/application"
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/google/uuid"
+)
+
+type amountResponse struct {
+	Amount    int64  `json:"amount"`
+	Formatted string `json:"formatted"`
+}
+
+type accountBalanceResponse struct {
+	AccountID uuid.UUID      `json:"account_id"`
+	Currency  string         `json:"currency"`
+	Posted    amountResponse `json:"posted"`
+	Pending   amountResponse `json:"pending"`
+	Available amountResponse `json:"available"`
+	AsOf      *time.Time     `json:"as_of,omitempty"`
+}
+
+func newAmountResponse(amount int64, currency string) amountResponse {
+	return amountResponse{
+		Amount:    amount,
+		Formatted: application.FormatAmount(amount, application.Currency(currency)),
+	}
+}
+
+func newAccountBalanceResponse(balance domain.AccountBalance) accountBalanceResponse {
+	response := accountBalanceResponse{
+		AccountID: balance.AccountID,
+		Currency:  balance.Currency,
+		Posted:    newAmountResponse(balance.Posted, balance.Currency),
+		Pending:   newAmountResponse(balance.Pending, balance.Currency),
+		Available: newAmountResponse(balance.Available, balance.Currency),
+	}
+	if !balance.AsOf.IsZero() {
+		response.AsOf = &balance.AsOf
+	}
+
+	return response
+}
+
+func (h *LedgerHandler) GetAccountBalanceHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+
+	var balance domain.AccountBalance
+	var err error
+	if raw := r.URL.Query().Get("as_of"); raw != "" {
+		asOf, parseErr := time.Parse(time.RFC3339, raw)
+		if parseErr != nil {
+			httputils.RespondError(w, http.StatusBadRequest, "as_of must be an RFC 3339 timestamp")
+			return
+		}
+		balance, err = h.ledgerService.GetAccountBalanceAsOf(r.Context(), id, asOf)
+	} else {
+		balance, err = h.ledgerService.GetAccountBalance(r.Context(), id)
+	}
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newAccountBalanceResponse(balance))
+}
+
+func (h *LedgerHandler) GetBalancesHandler(w http.ResponseWriter, r *http.Request) {
+	var ids []uuid.UUID
+	for _, raw := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			httputils.RespondError(w, http.StatusBadRequest, "invalid account id "+raw)
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	balances, err := h.ledgerService.GetAccountBalances(r.Context(), ids)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	response := make([]accountBalanceResponse, len(balances))
+	for i, balance := range balances {
+		response[i] = newAccountBalanceResponse(balance)
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
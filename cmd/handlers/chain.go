@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/google/uuid"
+)
+
+type brokenLinkResponse struct {
+	ChainSeq      int64     `json:"chain_seq"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Reason        string    `json:"reason"`
+}
+
+type chainVerificationResponse struct {
+	Valid     bool                `json:"valid"`
+	Checked   int64               `json:"checked"`
+	HeadSeq   int64               `json:"head_seq"`
+	HeadHash  string              `json:"head_hash,omitempty"`
+	Unchained int64               `json:"unchained"`
+	Broken    *brokenLinkResponse `json:"broken,omitempty"`
+}
+
+func (h *LedgerHandler) VerifyHashChainHandler(w http.ResponseWriter, r *http.Request) {
+	verification, err := h.ledgerService.VerifyHashChain(r.Context())
+	if err != nil {
+		httputils.RespondError(w, http.StatusInternalServerError, httputils.InternalSrvErrMsg)
+		return
+	}
+
+	response := chainVerificationResponse{
+		Valid:     verification.Valid,
+		Checked:   verification.Checked,
+		HeadSeq:   verification.HeadSeq,
+		HeadHash:  hex.EncodeToString(verification.HeadHash),
+		Unchained: verification.Unchained,
+	}
+	if broken := verification.Broken; broken != nil {
+		response.Broken = &brokenLinkResponse{
+			ChainSeq:      broken.ChainSeq,
+			TransactionID: broken.TransactionID,
+			Reason:        broken.Reason,
+		}
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
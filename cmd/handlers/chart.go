@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/google/uuid"
+)
+
+type accountNodeResponse struct {
+	ID            uuid.UUID             `json:"id"`
+	Name          string                `json:"name"`
+	Currency      string                `json:"currency"`
+	AccountClass  string                `json:"account_class"`
+	NormalBalance string                `json:"normal_balance"`
+	Balance       amountResponse        `json:"balance"`
+	Total         amountResponse        `json:"total"`
+	Children      []accountNodeResponse `json:"children,omitempty"`
+}
+
+func newAccountNodeResponse(node *domain.AccountNode) accountNodeResponse {
+	response := accountNodeResponse{
+		ID:            node.ID,
+		Name:          node.Name,
+		Currency:      node.Currency,
+		AccountClass:  node.Class,
+		NormalBalance: node.NormalBalance,
+		Balance:       newAmountResponse(node.Balance, node.Currency),
+		Total:         newAmountResponse(node.Total, node.Currency),
+	}
+	for _, child := range node.Children {
+		response.Children = append(response.Children, newAccountNodeResponse(child))
+	}
+
+	return response
+}
+
+func (h *LedgerHandler) GetAccountRollupHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+
+	root, err := h.ledgerService.GetAccountRollup(r.Context(), id)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newAccountNodeResponse(root))
+}
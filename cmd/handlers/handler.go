@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/IgorGrieder/Small-Ledger/internal/application"
 	"github.com/IgorGrieder/Small-Ledger/internal/domain"
@@ -24,6 +27,89 @@ type transactionRequest struct {
 	Currency       string    `json:"currency"`
 	Amount         int64     `json:"amount"`
 	IdempotencyKey uuid.UUID `json:"idempotency_key"`
+	QuoteID        uuid.UUID `json:"quote_id"`
+}
+
+type postingEntryRequest struct {
+	AccountID uuid.UUID       `json:"account_id"`
+	Amount    int64           `json:"amount"`
+	Currency  string          `json:"currency"`
+	Metadata  json.RawMessage `json:"metadata"`
+}
+
+type postingRequest struct {
+	IdempotencyKey uuid.UUID             `json:"idempotency_key"`
+	Description    string                `json:"description"`
+	CreatedBy      string                `json:"created_by"`
+	Entries        []postingEntryRequest `json:"entries"`
+	EffectiveAt    time.Time             `json:"effective_at"`
+	Metadata       json.RawMessage       `json:"metadata"`
+}
+
+type reversalRequest struct {
+	Amount         int64     `json:"amount"`
+	Reason         string    `json:"reason"`
+	CreatedBy      string    `json:"created_by"`
+	IdempotencyKey uuid.UUID `json:"idempotency_key"`
+}
+
+type entryResponse struct {
+	ID          uuid.UUID       `json:"id"`
+	AccountID   uuid.UUID       `json:"account_id"`
+	Amount      int64           `json:"amount"`
+	Currency    string          `json:"currency"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	EffectiveAt time.Time       `json:"effective_at"`
+}
+
+type transactionResponse struct {
+	ID                    uuid.UUID       `json:"id"`
+	ExternalID            string          `json:"external_id,omitempty"`
+	Description           string          `json:"description,omitempty"`
+	CreatedBy             string          `json:"created_by,omitempty"`
+	ReversesTransactionID *uuid.UUID      `json:"reverses_transaction_id,omitempty"`
+	Status                string          `json:"status"`
+	Metadata              json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt             time.Time       `json:"created_at"`
+	Entries               []entryResponse `json:"entries,omitempty"`
+	Hash                  string          `json:"hash,omitempty"`
+	PrevHash              string          `json:"prev_hash,omitempty"`
+	ChainSeq              int64           `json:"chain_seq,omitempty"`
+}
+
+func newTransactionResponse(result *domain.TransactionResult) transactionResponse {
+	entries := make([]entryResponse, len(result.Entries))
+	for i, entry := range result.Entries {
+		entries[i] = entryResponse{
+			ID:          entry.ID,
+			AccountID:   entry.AccountID,
+			Amount:      entry.Amount,
+			Currency:    entry.Currency,
+			Metadata:    entry.Metadata,
+			CreatedAt:   entry.CreatedAt,
+			EffectiveAt: entry.EffectiveAt,
+		}
+	}
+
+	response := transactionResponse{
+		ID:          result.ID,
+		ExternalID:  result.ExternalID,
+		Description: result.Description,
+		CreatedBy:   result.CreatedBy,
+		Status:      result.Status,
+		Metadata:    result.Metadata,
+		CreatedAt:   result.CreatedAt,
+		Entries:     entries,
+		Hash:        hex.EncodeToString(result.Hash),
+		PrevHash:    hex.EncodeToString(result.PrevHash),
+		ChainSeq:    result.ChainSeq,
+	}
+	if result.ReversesTransactionID != uuid.Nil {
+		response.ReversesTransactionID = &result.ReversesTransactionID
+	}
+
+	return response
 }
 
 func (h *LedgerHandler) TransactionHandler(w http.ResponseWriter, r *http.Request) {
@@ -39,28 +125,153 @@ func (h *LedgerHandler) TransactionHandler(w http.ResponseWriter, r *http.Reques
 		Currency:      request.Currency,
 		Value:         request.Amount,
 		CorrelationId: request.IdempotencyKey,
+		QuoteID:       request.QuoteID,
 	}
 
-	err = h.ledgerService.ProcessTransaction(r.Context(), transaction)
+	result, err := h.ledgerService.ProcessTransaction(r.Context(), transaction)
 	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	// Retries get the very same answer as the original request, the header
+	// only tells the client nothing new was posted
+	if result.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+
+	httputils.RespondJSON(w, http.StatusCreated, newTransactionResponse(result))
+}
+
+func (h *LedgerHandler) PostTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	var request postingRequest
+	err := httputils.DecodeJSON(w, r, &request)
+	if err != nil {
+		return
+	}
 
-		if errors.Is(err, application.ErrNotEnoughFunds) {
-			httputils.RespondError(w, http.StatusBadRequest, application.ErrNotEnoughFunds.Error())
-			return
+	posting := &domain.Posting{
+		IdempotencyKey: request.IdempotencyKey,
+		Description:    request.Description,
+		CreatedBy:      request.CreatedBy,
+		Entries:        make([]domain.EntryRequest, len(request.Entries)),
+		EffectiveAt:    request.EffectiveAt,
+		Metadata:       request.Metadata,
+	}
+	for i, entry := range request.Entries {
+		posting.Entries[i] = domain.EntryRequest{
+			AccountID: entry.AccountID,
+			Amount:    entry.Amount,
+			Currency:  entry.Currency,
+			Metadata:  entry.Metadata,
 		}
+	}
+
+	result, err := h.ledgerService.PostTransaction(r.Context(), posting)
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	if result.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+
+	httputils.RespondJSON(w, http.StatusCreated, newTransactionResponse(result))
+}
+
+func (h *LedgerHandler) ReverseTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	transactionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "invalid transaction id")
+		return
+	}
+
+	var request reversalRequest
+	err = httputils.DecodeJSON(w, r, &request)
+	if err != nil {
+		return
+	}
+
+	result, err := h.ledgerService.ReverseTransaction(r.Context(), &domain.Reversal{
+		TransactionID:  transactionID,
+		Amount:         request.Amount,
+		Reason:         request.Reason,
+		CreatedBy:      request.CreatedBy,
+		IdempotencyKey: request.IdempotencyKey,
+	})
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
 
+	if result.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+
+	httputils.RespondJSON(w, http.StatusCreated, newTransactionResponse(result))
+}
+
+// respondTransactionError maps the posting errors of the application layer
+// into HTTP responses, anything unknown is reported as an internal error.
+func respondTransactionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrNotEnoughFunds),
+		errors.Is(err, application.ErrInvalidTransaction),
+		errors.Is(err, application.ErrInvalidEntry),
+		errors.Is(err, application.ErrUnbalancedTransaction),
+		errors.Is(err, application.ErrCurrencyMismatch),
+		errors.Is(err, application.ErrInvalidReversal),
+		errors.Is(err, application.ErrInvalidCursor),
+		errors.Is(err, application.ErrInvalidHold),
+		errors.Is(err, application.ErrInvalidQuote):
+		httputils.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, application.ErrAlreadyReversed),
+		errors.Is(err, application.ErrNotReversible),
+		errors.Is(err, application.ErrAccountClosed),
+		errors.Is(err, application.ErrHoldNotPending),
+		errors.Is(err, application.ErrHoldExpired),
+		errors.Is(err, application.ErrQuoteExpired),
+		errors.Is(err, application.ErrQuoteUsed):
+		httputils.RespondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, application.ErrAccountNotFound),
+		errors.Is(err, application.ErrTransactionNotFound),
+		errors.Is(err, application.ErrHoldNotFound),
+		errors.Is(err, application.ErrQuoteNotFound):
+		httputils.RespondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, application.ErrRateUnavailable),
+		errors.Is(err, application.ErrAccountBusy):
+		httputils.RespondError(w, http.StatusServiceUnavailable, err.Error())
+	default:
 		httputils.RespondError(w, http.StatusInternalServerError, httputils.InternalSrvErrMsg)
 	}
+}
 
-	httputils.RespondSuccess(w, http.StatusAccepted)
+type balanceDriftResponse struct {
+	AccountID    uuid.UUID `json:"account_id"`
+	Currency     string    `json:"currency"`
+	Materialized int64     `json:"materialized"`
+	Computed     int64     `json:"computed"`
+	Drift        int64     `json:"drift"`
 }
 
-func (h *LedgerHandler) GetAccountsHandler(w http.ResponseWriter, r *http.Request) {
-	accounts, err := h.ledgerService.GetAllAccounts(r.Context())
+func (h *LedgerHandler) BalanceDriftHandler(w http.ResponseWriter, r *http.Request) {
+	drifts, err := h.ledgerService.VerifyBalances(r.Context())
 	if err != nil {
 		httputils.RespondError(w, http.StatusInternalServerError, httputils.InternalSrvErrMsg)
 		return
 	}
 
-	httputils.RespondJSON(w, http.StatusOK, accounts)
+	response := make([]balanceDriftResponse, len(drifts))
+	for i, drift := range drifts {
+		response[i] = balanceDriftResponse{
+			AccountID:    drift.AccountID,
+			Currency:     drift.Currency,
+			Materialized: drift.Materialized,
+			Computed:     drift.Computed,
+			Drift:        drift.Materialized - drift.Computed,
+		}
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
 }
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/google/uuid"
+)
+
+type createHoldRequest struct {
+	IdempotencyKey   uuid.UUID       `json:"idempotency_key"`
+	AccountID        uuid.UUID       `json:"account_id"`
+	CounterpartyID   uuid.UUID       `json:"counterparty_id"`
+	Currency         string          `json:"currency"`
+	Amount           int64           `json:"amount"`
+	Description      string          `json:"description"`
+	CreatedBy        string          `json:"created_by"`
+	Metadata         json.RawMessage `json:"metadata"`
+	ExpiresInSeconds int64           `json:"expires_in_seconds"`
+}
+
+type captureHoldRequest struct {
+	Amount int64 `json:"amount"`
+}
+
+type holdResponse struct {
+	ID             uuid.UUID `json:"id"`
+	TransactionID  uuid.UUID `json:"transaction_id"`
+	AccountID      uuid.UUID `json:"account_id"`
+	CounterpartyID uuid.UUID `json:"counterparty_id"`
+	Currency       string    `json:"currency"`
+	Amount         int64     `json:"amount"`
+	CapturedAmount int64     `json:"captured_amount"`
+	Status         string    `json:"status"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func newHoldResponse(hold *domain.Hold) holdResponse {
+	return holdResponse{
+		ID:             hold.ID,
+		TransactionID:  hold.TransactionID,
+		AccountID:      hold.AccountID,
+		CounterpartyID: hold.CounterpartyID,
+		Currency:       hold.Currency,
+		Amount:         hold.Amount,
+		CapturedAmount: hold.CapturedAmount,
+		Status:         hold.Status,
+		ExpiresAt:      hold.ExpiresAt,
+		CreatedAt:      hold.CreatedAt,
+	}
+}
+
+func (h *LedgerHandler) CreateHoldHandler(w http.ResponseWriter, r *http.Request) {
+	var request createHoldRequest
+	err := httputils.DecodeJSON(w, r, &request)
+	if err != nil {
+		return
+	}
+
+	hold, err := h.ledgerService.CreateHold(r.Context(), &domain.NewHold{
+		IdempotencyKey: request.IdempotencyKey,
+		AccountID:      request.AccountID,
+		CounterpartyID: request.CounterpartyID,
+		Currency:       request.Currency,
+		Amount:         request.Amount,
+		Description:    request.Description,
+		CreatedBy:      request.CreatedBy,
+		Metadata:       request.Metadata,
+		TTL:            time.Duration(request.ExpiresInSeconds) * time.Second,
+	})
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	if hold.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+
+	httputils.RespondJSON(w, http.StatusCreated, newHoldResponse(hold))
+}
+
+func (h *LedgerHandler) GetHoldHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := holdID(w, r)
+	if !ok {
+		return
+	}
+
+	hold, err := h.ledgerService.GetHold(r.Context(), id)
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newHoldResponse(hold))
+}
+
+func (h *LedgerHandler) CaptureHoldHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := holdID(w, r)
+	if !ok {
+		return
+	}
+
+	// An empty body captures the whole hold
+	var request captureHoldRequest
+	if r.ContentLength != 0 {
+		if err := httputils.DecodeJSON(w, r, &request); err != nil {
+			return
+		}
+	}
+
+	result, err := h.ledgerService.CaptureHold(r.Context(), id, request.Amount)
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newTransactionResponse(result))
+}
+
+func (h *LedgerHandler) VoidHoldHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := holdID(w, r)
+	if !ok {
+		return
+	}
+
+	hold, err := h.ledgerService.VoidHold(r.Context(), id)
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newHoldResponse(hold))
+}
+
+func holdID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "invalid hold id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/google/uuid"
+)
+
+type createQuoteRequest struct {
+	SourceCurrency string `json:"source_currency"`
+	TargetCurrency string `json:"target_currency"`
+	Amount         int64  `json:"amount"`
+}
+
+type quoteResponse struct {
+	ID            uuid.UUID      `json:"id"`
+	Pair          string         `json:"pair"`
+	SourceAmount  amountResponse `json:"source_amount"`
+	TargetAmount  amountResponse `json:"target_amount"`
+	MidRate       string         `json:"mid_rate"`
+	SpreadBps     int32          `json:"spread_bps"`
+	Rate          string         `json:"rate"`
+	RateSource    string         `json:"rate_source"`
+	ExpiresAt     time.Time      `json:"expires_at"`
+	TransactionID *uuid.UUID     `json:"transaction_id,omitempty"`
+	UsedAt        *time.Time     `json:"used_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+func newQuoteResponse(quote *domain.Quote) quoteResponse {
+	response := quoteResponse{
+		ID:           quote.ID,
+		Pair:         quote.Rate.Pair.String(),
+		SourceAmount: newAmountResponse(quote.SourceAmount, quote.SourceCurrency),
+		TargetAmount: newAmountResponse(quote.TargetAmount, quote.TargetCurrency),
+		MidRate:      quote.MidRate.Decimal(),
+		SpreadBps:    quote.SpreadBps,
+		Rate:         quote.Rate.Decimal(),
+		RateSource:   quote.Rate.Source,
+		ExpiresAt:    quote.ExpiresAt,
+		CreatedAt:    quote.CreatedAt,
+	}
+	if quote.TransactionID != uuid.Nil {
+		response.TransactionID = &quote.TransactionID
+		response.UsedAt = &quote.UsedAt
+	}
+
+	return response
+}
+
+func (h *LedgerHandler) CreateQuoteHandler(w http.ResponseWriter, r *http.Request) {
+	var request createQuoteRequest
+	err := httputils.DecodeJSON(w, r, &request)
+	if err != nil {
+		return
+	}
+
+	quote, err := h.ledgerService.CreateQuote(r.Context(), &domain.NewQuote{
+		SourceCurrency: request.SourceCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Amount:         request.Amount,
+	})
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusCreated, newQuoteResponse(quote))
+}
+
+func (h *LedgerHandler) GetQuoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "invalid quote id")
+		return
+	}
+
+	quote, err := h.ledgerService.GetQuote(r.Context(), id)
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newQuoteResponse(quote))
+}
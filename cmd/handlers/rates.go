@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/application"
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+)
+
+type rateResponse struct {
+	Pair   string    `json:"pair"`
+	Rate   string    `json:"rate"`
+	Source string    `json:"source"`
+	AsOf   time.Time `json:"as_of"`
+}
+
+func newRateResponse(rate fx.Rate) rateResponse {
+	return rateResponse{
+		Pair:   rate.Pair.String(),
+		Rate:   rate.Decimal(),
+		Source: rate.Source,
+		AsOf:   rate.AsOf,
+	}
+}
+
+func (h *LedgerHandler) GetRateHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pair, err := fx.ParsePair(query.Get("pair"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "pair must look like USDBRL")
+		return
+	}
+	at, err := timeParam(query.Get("at"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "at must be an RFC 3339 timestamp")
+		return
+	}
+
+	rate, err := h.ledgerService.GetRate(r.Context(), pair, at)
+	if err != nil {
+		switch {
+		case errors.Is(err, fx.ErrInvalidPair):
+			httputils.RespondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, application.ErrRateNotFound):
+			httputils.RespondError(w, http.StatusNotFound, err.Error())
+		default:
+			httputils.RespondError(w, http.StatusInternalServerError, httputils.InternalSrvErrMsg)
+		}
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newRateResponse(rate))
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/application"
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/google/uuid"
+)
+
+type accountTotalsResponse struct {
+	AccountID     uuid.UUID      `json:"account_id"`
+	Name          string         `json:"name"`
+	AccountClass  string         `json:"account_class"`
+	NormalBalance string         `json:"normal_balance"`
+	Debits        amountResponse `json:"debits"`
+	Credits       amountResponse `json:"credits"`
+	Balance       amountResponse `json:"balance"`
+}
+
+type trialBalanceCurrencyResponse struct {
+	Currency string                  `json:"currency"`
+	Accounts []accountTotalsResponse `json:"accounts"`
+	Debits   amountResponse          `json:"debits"`
+	Credits  amountResponse          `json:"credits"`
+	Balanced bool                    `json:"balanced"`
+}
+
+type trialBalanceResponse struct {
+	AsOf       time.Time                      `json:"as_of"`
+	Currencies []trialBalanceCurrencyResponse `json:"currencies"`
+	Balanced   bool                           `json:"balanced"`
+}
+
+type reportSectionResponse struct {
+	AccountClass string                  `json:"account_class"`
+	Accounts     []accountTotalsResponse `json:"accounts"`
+	Total        amountResponse          `json:"total"`
+}
+
+type balanceSheetCurrencyResponse struct {
+	Currency    string                `json:"currency"`
+	Assets      reportSectionResponse `json:"assets"`
+	Liabilities reportSectionResponse `json:"liabilities"`
+	Equity      reportSectionResponse `json:"equity"`
+	NetIncome   amountResponse        `json:"net_income"`
+	Balanced    bool                  `json:"balanced"`
+}
+
+type balanceSheetResponse struct {
+	AsOf       time.Time                      `json:"as_of"`
+	Currencies []balanceSheetCurrencyResponse `json:"currencies"`
+	Balanced   bool                           `json:"balanced"`
+}
+
+type incomeStatementCurrencyResponse struct {
+	Currency  string                `json:"currency"`
+	Revenue   reportSectionResponse `json:"revenue"`
+	Expenses  reportSectionResponse `json:"expenses"`
+	NetIncome amountResponse        `json:"net_income"`
+}
+
+type incomeStatementResponse struct {
+	From       *time.Time                        `json:"from,omitempty"`
+	To         time.Time                         `json:"to"`
+	Currencies []incomeStatementCurrencyResponse `json:"currencies"`
+}
+
+type revaluationLineResponse struct {
+	AccountID      uuid.UUID      `json:"account_id"`
+	Name           string         `json:"name"`
+	AccountClass   string         `json:"account_class"`
+	NormalBalance  string         `json:"normal_balance"`
+	Balance        amountResponse `json:"balance"`
+	CarryingValue  amountResponse `json:"carrying_value"`
+	RevaluedValue  amountResponse `json:"revalued_value"`
+	UnrealizedGain amountResponse `json:"unrealized_gain"`
+}
+
+type revaluationResponse struct {
+	AsOf           time.Time                 `json:"as_of"`
+	Currency       string                    `json:"currency"`
+	Rates          []rateResponse            `json:"rates"`
+	Accounts       []revaluationLineResponse `json:"accounts"`
+	UnrealizedGain amountResponse            `json:"unrealized_gain"`
+}
+
+func newAccountTotalsResponses(totals []domain.AccountTotals) []accountTotalsResponse {
+	response := make([]accountTotalsResponse, len(totals))
+	for i, total := range totals {
+		response[i] = accountTotalsResponse{
+			AccountID:     total.AccountID,
+			Name:          total.Name,
+			AccountClass:  total.Class,
+			NormalBalance: total.NormalBalance,
+			Debits:        newAmountResponse(total.Debits, total.Currency),
+			Credits:       newAmountResponse(total.Credits, total.Currency),
+			Balance:       newAmountResponse(total.Balance, total.Currency),
+		}
+	}
+	return response
+}
+
+func newReportSectionResponse(section domain.ReportSection, currency string) reportSectionResponse {
+	return reportSectionResponse{
+		AccountClass: section.Class,
+		Accounts:     newAccountTotalsResponses(section.Accounts),
+		Total:        newAmountResponse(section.Total, currency),
+	}
+}
+
+func (h *LedgerHandler) TrialBalanceHandler(w http.ResponseWriter, r *http.Request) {
+	asOf, err := timeParam(r.URL.Query().Get("as_of"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "as_of must be an RFC 3339 timestamp")
+		return
+	}
+
+	report, err := h.ledgerService.GetTrialBalance(r.Context(), asOf)
+	if err != nil {
+		respondReportError(w, err)
+		return
+	}
+
+	response := trialBalanceResponse{
+		AsOf:       report.AsOf,
+		Currencies: make([]trialBalanceCurrencyResponse, len(report.Currencies)),
+		Balanced:   report.Balanced,
+	}
+	for i, currency := range report.Currencies {
+		response.Currencies[i] = trialBalanceCurrencyResponse{
+			Currency: currency.Currency,
+			Accounts: newAccountTotalsResponses(currency.Accounts),
+			Debits:   newAmountResponse(currency.Debits, currency.Currency),
+			Credits:  newAmountResponse(currency.Credits, currency.Currency),
+			Balanced: currency.Balanced,
+		}
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
+
+func (h *LedgerHandler) BalanceSheetHandler(w http.ResponseWriter, r *http.Request) {
+	asOf, err := timeParam(r.URL.Query().Get("as_of"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "as_of must be an RFC 3339 timestamp")
+		return
+	}
+
+	report, err := h.ledgerService.GetBalanceSheet(r.Context(), asOf)
+	if err != nil {
+		respondReportError(w, err)
+		return
+	}
+
+	response := balanceSheetResponse{
+		AsOf:       report.AsOf,
+		Currencies: make([]balanceSheetCurrencyResponse, len(report.Currencies)),
+		Balanced:   report.Balanced,
+	}
+	for i, currency := range report.Currencies {
+		response.Currencies[i] = balanceSheetCurrencyResponse{
+			Currency:    currency.Currency,
+			Assets:      newReportSectionResponse(currency.Assets, currency.Currency),
+			Liabilities: newReportSectionResponse(currency.Liabilities, currency.Currency),
+			Equity:      newReportSectionResponse(currency.Equity, currency.Currency),
+			NetIncome:   newAmountResponse(currency.NetIncome, currency.Currency),
+			Balanced:    currency.Balanced,
+		}
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
+
+func (h *LedgerHandler) IncomeStatementHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, err := timeParam(query.Get("from"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "from must be an RFC 3339 timestamp")
+		return
+	}
+	to, err := timeParam(query.Get("to"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "to must be an RFC 3339 timestamp")
+		return
+	}
+
+	report, err := h.ledgerService.GetIncomeStatement(r.Context(), from, to)
+	if err != nil {
+		respondReportError(w, err)
+		return
+	}
+
+	response := incomeStatementResponse{
+		To:         report.To,
+		Currencies: make([]incomeStatementCurrencyResponse, len(report.Currencies)),
+	}
+	if !report.From.IsZero() {
+		response.From = &report.From
+	}
+	for i, currency := range report.Currencies {
+		response.Currencies[i] = incomeStatementCurrencyResponse{
+			Currency:  currency.Currency,
+			Revenue:   newReportSectionResponse(currency.Revenue, currency.Currency),
+			Expenses:  newReportSectionResponse(currency.Expenses, currency.Currency),
+			NetIncome: newAmountResponse(currency.NetIncome, currency.Currency),
+		}
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
+
+func (h *LedgerHandler) RevaluationHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	asOf, err := timeParam(query.Get("as_of"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "as_of must be an RFC 3339 timestamp")
+		return
+	}
+
+	// The books are kept in dollars unless finance asks otherwise
+	currency := query.Get("currency")
+	if currency == "" {
+		currency = string(application.CurrencyUSD)
+	}
+
+	report, err := h.ledgerService.GetRevaluation(r.Context(), currency, asOf)
+	if err != nil {
+		respondReportError(w, err)
+		return
+	}
+
+	response := revaluationResponse{
+		AsOf:           report.AsOf,
+		Currency:       report.Currency,
+		Rates:          make([]rateResponse, len(report.Rates)),
+		Accounts:       make([]revaluationLineResponse, len(report.Accounts)),
+		UnrealizedGain: newAmountResponse(report.UnrealizedGain, report.Currency),
+	}
+	for i, rate := range report.Rates {
+		response.Rates[i] = newRateResponse(rate)
+	}
+	for i, line := range report.Accounts {
+		response.Accounts[i] = revaluationLineResponse{
+			AccountID:      line.AccountID,
+			Name:           line.Name,
+			AccountClass:   line.Class,
+			NormalBalance:  line.NormalBalance,
+			Balance:        newAmountResponse(line.Balance, line.Currency),
+			CarryingValue:  newAmountResponse(line.CarryingValue, report.Currency),
+			RevaluedValue:  newAmountResponse(line.RevaluedValue, report.Currency),
+			UnrealizedGain: newAmountResponse(line.UnrealizedGain, report.Currency),
+		}
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
+
+func respondReportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrInvalidReport):
+		httputils.RespondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, application.ErrRateNotFound):
+		httputils.RespondError(w, http.StatusNotFound, err.Error())
+	default:
+		httputils.RespondError(w, http.StatusInternalServerError, httputils.InternalSrvErrMsg)
+	}
+}
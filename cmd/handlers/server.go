@@ -16,7 +16,36 @@ func StartServer(ledger *application.LedgerService, cfg *cfg.Config) {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("POST /transaction", ledgerHandler.TransactionHandler)
+	mux.HandleFunc("POST /transactions", ledgerHandler.PostTransactionHandler)
+	mux.HandleFunc("POST /transactions/{id}/reverse", ledgerHandler.ReverseTransactionHandler)
+	mux.HandleFunc("GET /transactions", ledgerHandler.SearchTransactionsHandler)
+	mux.HandleFunc("GET /transactions/{id}", ledgerHandler.GetTransactionHandler)
 	mux.HandleFunc("GET /accounts", ledgerHandler.GetAccountsHandler)
+	mux.HandleFunc("POST /accounts", ledgerHandler.CreateAccountHandler)
+	mux.HandleFunc("GET /accounts/{id}", ledgerHandler.GetAccountHandler)
+	mux.HandleFunc("PATCH /accounts/{id}/metadata", ledgerHandler.UpdateAccountMetadataHandler)
+	mux.HandleFunc("PUT /accounts/{id}/policy", ledgerHandler.UpdateBalancePolicyHandler)
+	mux.HandleFunc("PUT /accounts/{id}/parent", ledgerHandler.UpdateAccountParentHandler)
+	mux.HandleFunc("GET /accounts/{id}/rollup", ledgerHandler.GetAccountRollupHandler)
+	mux.HandleFunc("POST /accounts/{id}/close", ledgerHandler.CloseAccountHandler)
+	mux.HandleFunc("GET /accounts/{id}/balance", ledgerHandler.GetAccountBalanceHandler)
+	mux.HandleFunc("GET /accounts/{id}/entries", ledgerHandler.GetAccountStatementHandler)
+	mux.HandleFunc("GET /balances", ledgerHandler.GetBalancesHandler)
+	mux.HandleFunc("POST /holds", ledgerHandler.CreateHoldHandler)
+	mux.HandleFunc("GET /holds/{id}", ledgerHandler.GetHoldHandler)
+	mux.HandleFunc("POST /holds/{id}/capture", ledgerHandler.CaptureHoldHandler)
+	mux.HandleFunc("POST /holds/{id}/void", ledgerHandler.VoidHoldHandler)
+	mux.HandleFunc("POST /fx/quotes", ledgerHandler.CreateQuoteHandler)
+	mux.HandleFunc("GET /fx/quotes/{id}", ledgerHandler.GetQuoteHandler)
+	mux.HandleFunc("GET /fx/rates", ledgerHandler.GetRateHandler)
+	mux.HandleFunc("GET /balances/drift", ledgerHandler.BalanceDriftHandler)
+	mux.HandleFunc("GET /transactions/chain/verify", ledgerHandler.VerifyHashChainHandler)
+	mux.HandleFunc("GET /transactions/{id}/proof", ledgerHandler.GetTransactionProofHandler)
+	mux.HandleFunc("GET /attestations/{day}", ledgerHandler.GetAttestationHandler)
+	mux.HandleFunc("GET /reports/trial-balance", ledgerHandler.TrialBalanceHandler)
+	mux.HandleFunc("GET /reports/balance-sheet", ledgerHandler.BalanceSheetHandler)
+	mux.HandleFunc("GET /reports/income-statement", ledgerHandler.IncomeStatementHandler)
+	mux.HandleFunc("GET /reports/revaluation", ledgerHandler.RevaluationHandler)
 
 	srv := &http.Server{Addr: fmt.Sprintf(":%d", cfg.APPLICATION_PORT), Handler: mux}
 
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/google/uuid"
+)
+
+type statementLineResponse struct {
+	EntryID          uuid.UUID       `json:"entry_id"`
+	TransactionID    uuid.UUID       `json:"transaction_id"`
+	Amount           amountResponse  `json:"amount"`
+	Currency         string          `json:"currency"`
+	RunningBalance   amountResponse  `json:"running_balance"`
+	Description      string          `json:"description,omitempty"`
+	CounterpartyID   *uuid.UUID      `json:"counterparty_id,omitempty"`
+	CounterpartyName string          `json:"counterparty_name,omitempty"`
+	Metadata         json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	EffectiveAt      time.Time       `json:"effective_at"`
+}
+
+type statementResponse struct {
+	Entries    []statementLineResponse `json:"entries"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+func (h *LedgerHandler) GetAccountStatementHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := accountID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := statementFilter(w, r)
+	if !ok {
+		return
+	}
+
+	statement, err := h.ledgerService.GetAccountStatement(r.Context(), id, filter)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	response := statementResponse{
+		Entries:    make([]statementLineResponse, len(statement.Lines)),
+		NextCursor: statement.NextCursor,
+	}
+	for i, line := range statement.Lines {
+		response.Entries[i] = statementLineResponse{
+			EntryID:          line.EntryID,
+			TransactionID:    line.TransactionID,
+			Amount:           newAmountResponse(line.Amount, line.Currency),
+			Currency:         line.Currency,
+			RunningBalance:   newAmountResponse(line.RunningBalance, line.Currency),
+			Description:      line.Description,
+			CounterpartyName: line.CounterpartyName,
+			Metadata:         line.Metadata,
+			CreatedAt:        line.CreatedAt,
+			EffectiveAt:      line.EffectiveAt,
+		}
+		if line.CounterpartyID != uuid.Nil {
+			response.Entries[i].CounterpartyID = &line.CounterpartyID
+		}
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
+
+func statementFilter(w http.ResponseWriter, r *http.Request) (domain.StatementFilter, bool) {
+	query := r.URL.Query()
+	filter := domain.StatementFilter{Cursor: query.Get("cursor")}
+
+	var err error
+	if filter.From, err = timeParam(query.Get("from")); err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "from must be an RFC 3339 timestamp")
+		return filter, false
+	}
+	if filter.To, err = timeParam(query.Get("to")); err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "to must be an RFC 3339 timestamp")
+		return filter, false
+	}
+	if filter.MinAmount, err = int64Param(query.Get("min_amount")); err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "min_amount must be an integer amount in minor units")
+		return filter, false
+	}
+	if filter.MaxAmount, err = int64Param(query.Get("max_amount")); err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "max_amount must be an integer amount in minor units")
+		return filter, false
+	}
+	if raw := query.Get("limit"); raw != "" {
+		if filter.Limit, err = strconv.Atoi(raw); err != nil {
+			httputils.RespondError(w, http.StatusBadRequest, "limit must be an integer")
+			return filter, false
+		}
+	}
+
+	return filter, true
+}
+
+func timeParam(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+func int64Param(raw string) (*int64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/google/uuid"
+)
+
+type transactionPageResponse struct {
+	Transactions []transactionResponse `json:"transactions"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
+}
+
+func (h *LedgerHandler) GetTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "invalid transaction id")
+		return
+	}
+
+	result, err := h.ledgerService.GetTransaction(r.Context(), id)
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, newTransactionResponse(result))
+}
+
+func (h *LedgerHandler) SearchTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.TransactionFilter{
+		Status:     query.Get("status"),
+		ExternalID: query.Get("external_id"),
+		CreatedBy:  query.Get("created_by"),
+		Cursor:     query.Get("cursor"),
+	}
+
+	var err error
+	if filter.From, err = timeParam(query.Get("from")); err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "from must be an RFC 3339 timestamp")
+		return
+	}
+	if filter.To, err = timeParam(query.Get("to")); err != nil {
+		httputils.RespondError(w, http.StatusBadRequest, "to must be an RFC 3339 timestamp")
+		return
+	}
+	if raw := query.Get("account_id"); raw != "" {
+		if filter.AccountID, err = uuid.Parse(raw); err != nil {
+			httputils.RespondError(w, http.StatusBadRequest, "invalid account id")
+			return
+		}
+	}
+	if raw := query.Get("metadata"); raw != "" {
+		filter.Metadata = []byte(raw)
+	}
+	if raw := query.Get("limit"); raw != "" {
+		if filter.Limit, err = strconv.Atoi(raw); err != nil {
+			httputils.RespondError(w, http.StatusBadRequest, "limit must be an integer")
+			return
+		}
+	}
+
+	page, err := h.ledgerService.SearchTransactions(r.Context(), filter)
+	if err != nil {
+		respondTransactionError(w, err)
+		return
+	}
+
+	response := transactionPageResponse{
+		Transactions: make([]transactionResponse, len(page.Transactions)),
+		NextCursor:   page.NextCursor,
+	}
+	for i := range page.Transactions {
+		response.Transactions[i] = newTransactionResponse(&page.Transactions[i])
+	}
+
+	httputils.RespondJSON(w, http.StatusOK, response)
+}
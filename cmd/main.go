@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/IgorGrieder/Small-Ledger/cmd/handlers"
 	"github.com/IgorGrieder/Small-Ledger/internal/application"
 	"github.com/IgorGrieder/Small-Ledger/internal/cfg"
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
 	"github.com/IgorGrieder/Small-Ledger/internal/http/httpclient"
 	"github.com/IgorGrieder/Small-Ledger/internal/repo"
 )
@@ -33,7 +35,37 @@ func main() {
 	// Http base client
 	httpClient := httpclient.NewClient(60*time.Second, 5, 10*time.Second)
 
-	ledgerService := application.NewLedgerService(cfg, store, redis, httpClient)
+	// FX rates come from the vendor API, cached in Redis, and fall back to the
+	// fixed rates file when one is configured. Every rate quoted is recorded
+	// for the historical lookups
+	rateRecorder := application.NewRateRecorder(store)
+
+	var rateProviders []fx.RateProvider
+	if cfg.CURRENCY_URL != "" {
+		vendor := fx.NewRecordingProvider(fx.NewHTTPProvider(httpClient, cfg.CURRENCY_URL), rateRecorder)
+		rateProviders = append(rateProviders, fx.NewCachedProvider(vendor, redis, 1*time.Hour))
+	}
+	if cfg.FX_RATES_FILE != "" {
+		static, err := fx.LoadStaticProvider(cfg.FX_RATES_FILE)
+		if err != nil {
+			slog.Error("failed loading the fx rates file",
+				slog.String("error", err.Error()),
+			)
+			os.Exit(1)
+		}
+		rateProviders = append(rateProviders, fx.NewRecordingProvider(static, rateRecorder))
+	}
+
+	ledgerService := application.NewLedgerService(cfg, store, fx.NewChain(rateProviders...))
+
+	// Background balance snapshots keep as-of lookups bounded
+	go ledgerService.RunBalanceSnapshots(context.Background())
+	// Releases the funds of holds nobody captured or voided in time
+	go ledgerService.RunHoldExpiry(context.Background())
+	// Publishes a Merkle root over every closed day of linked transactions
+	go ledgerService.RunAttestations(context.Background())
+	// Appends every posted transaction to the hash chain
+	go ledgerService.RunHashChain(context.Background())
 
 	handlers.StartServer(ledgerService, cfg)
 }
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/application"
+	"github.com/IgorGrieder/Small-Ledger/internal/cfg"
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+)
+
+// Exit codes, so a nightly job can tell a corrupted ledger from a failed run
+const (
+	exitOK         = 0
+	exitViolations = 1
+	exitError      = 2
+)
+
+type unbalancedTransaction struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Currency      string    `json:"currency"`
+	Total         int64     `json:"total"`
+}
+
+type currencyMismatch struct {
+	EntryID         uuid.UUID `json:"entry_id"`
+	TransactionID   uuid.UUID `json:"transaction_id"`
+	AccountID       uuid.UUID `json:"account_id"`
+	EntryCurrency   string    `json:"entry_currency"`
+	AccountCurrency string    `json:"account_currency"`
+}
+
+type orphanTransaction struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	ExternalID    string    `json:"external_id,omitempty"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type balanceDrift struct {
+	AccountID    uuid.UUID `json:"account_id"`
+	Currency     string    `json:"currency"`
+	Materialized int64     `json:"materialized"`
+	Computed     int64     `json:"computed"`
+}
+
+type snapshotDrift struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Currency  string    `json:"currency"`
+	UpToSeq   int64     `json:"up_to_seq"`
+	Snapshot  int64     `json:"snapshot"`
+	Computed  int64     `json:"computed"`
+}
+
+type duplicateExternalID struct {
+	ExternalID     string      `json:"external_id"`
+	TransactionIDs []uuid.UUID `json:"transaction_ids"`
+}
+
+type report struct {
+	CheckedAt              time.Time               `json:"checked_at"`
+	OK                     bool                    `json:"ok"`
+	Violations             int                     `json:"violations"`
+	UnbalancedTransactions []unbalancedTransaction `json:"unbalanced_transactions"`
+	CurrencyMismatches     []currencyMismatch      `json:"currency_mismatches"`
+	OrphanTransactions     []orphanTransaction     `json:"orphan_transactions"`
+	BalanceDrifts          []balanceDrift          `json:"balance_drifts"`
+	SnapshotDrifts         []snapshotDrift         `json:"snapshot_drifts"`
+	DuplicateExternalIDs   []duplicateExternalID   `json:"duplicate_external_ids"`
+}
+
+func newReport(integrity domain.IntegrityReport) report {
+	r := report{
+		CheckedAt:              integrity.CheckedAt,
+		Violations:             integrity.Violations(),
+		UnbalancedTransactions: make([]unbalancedTransaction, len(integrity.UnbalancedTransactions)),
+		CurrencyMismatches:     make([]currencyMismatch, len(integrity.CurrencyMismatches)),
+		OrphanTransactions:     make([]orphanTransaction, len(integrity.OrphanTransactions)),
+		BalanceDrifts:          make([]balanceDrift, len(integrity.BalanceDrifts)),
+		SnapshotDrifts:         make([]snapshotDrift, len(integrity.SnapshotDrifts)),
+		DuplicateExternalIDs:   make([]duplicateExternalID, len(integrity.DuplicateExternalIDs)),
+	}
+	r.OK = r.Violations == 0
+
+	for i, v := range integrity.UnbalancedTransactions {
+		r.UnbalancedTransactions[i] = unbalancedTransaction(v)
+	}
+	for i, v := range integrity.CurrencyMismatches {
+		r.CurrencyMismatches[i] = currencyMismatch(v)
+	}
+	for i, v := range integrity.OrphanTransactions {
+		r.OrphanTransactions[i] = orphanTransaction(v)
+	}
+	for i, v := range integrity.BalanceDrifts {
+		r.BalanceDrifts[i] = balanceDrift(v)
+	}
+	for i, v := range integrity.SnapshotDrifts {
+		r.SnapshotDrifts[i] = snapshotDrift(v)
+	}
+	for i, v := range integrity.DuplicateExternalIDs {
+		r.DuplicateExternalIDs[i] = duplicateExternalID(v)
+	}
+
+	return r
+}
+
+// verify scans the ledger pointed at by the PG_* environment and writes a
+// JSON report of every invariant violation to stdout. Logs go to stderr so
+// the report can be piped as is.
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	slog.SetDefault(logger)
+
+	os.Exit(run())
+}
+
+func run() int {
+	cfg := cfg.NewConfig()
+	pgConn := repo.SetupPg(cfg)
+	defer pgConn.Close()
+
+	integrity, err := application.NewVerifier(repo.NewStore(pgConn)).Verify(context.Background())
+	if err != nil {
+		slog.Error("error verifying the ledger", slog.String("error", err.Error()))
+		return exitError
+	}
+
+	r := newReport(integrity)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		slog.Error("error writing the report", slog.String("error", err.Error()))
+		return exitError
+	}
+
+	if !r.OK {
+		slog.Error("ledger invariants violated", slog.Int("violations", r.Violations))
+		return exitViolations
+	}
+
+	slog.Info("ledger verified")
+	return exitOK
+}
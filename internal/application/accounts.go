@@ -0,0 +1,221 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrInvalidAccount    error = errors.New("invalid account")
+	ErrAccountClosed     error = errors.New("account is closed")
+	ErrAccountHasBalance error = errors.New("account balance must be zero to close it")
+)
+
+// CreateAccount opens a new account together with its zeroed balance row.
+func (l *LedgerService) CreateAccount(ctx context.Context, newAccount *domain.NewAccount) (repo.Account, error) {
+	if strings.TrimSpace(newAccount.Name) == "" {
+		return repo.Account{}, fmt.Errorf("%w: name is required", ErrInvalidAccount)
+	}
+	if !Currency(newAccount.Currency).IsSupported() {
+		return repo.Account{}, fmt.Errorf("%w: unsupported currency %q", ErrInvalidAccount, newAccount.Currency)
+	}
+
+	metadata, err := accountMetadata(newAccount.Metadata)
+	if err != nil {
+		return repo.Account{}, err
+	}
+
+	policy := newAccount.BalancePolicy
+	if policy == "" {
+		policy = domain.BalancePolicyNonNegative
+	}
+	if err := validateBalancePolicy(policy, newAccount.OverdraftLimit); err != nil {
+		return repo.Account{}, err
+	}
+
+	class := repo.AccountClass(newAccount.Class)
+	if class == "" {
+		class = repo.AccountClassLiability
+	}
+	side, err := normalBalance(class)
+	if err != nil {
+		return repo.Account{}, err
+	}
+
+	if newAccount.ParentID != uuid.Nil {
+		if err := validateParent(ctx, l.store.Queries, class, newAccount.Currency, newAccount.ParentID); err != nil {
+			return repo.Account{}, err
+		}
+	}
+
+	var account repo.Account
+	err = l.runInTx(ctx, func(qtx *repo.Queries) error {
+		var err error
+		account, err = qtx.CreateAccount(ctx, repo.CreateAccountParams{
+			ID:             uuid.New(),
+			Name:           newAccount.Name,
+			Currency:       repo.Currency(newAccount.Currency),
+			Metadata:       metadata,
+			BalancePolicy:  policy,
+			OverdraftLimit: newAccount.OverdraftLimit,
+			AccountClass:   class,
+			NormalBalance:  side,
+			ParentID:       uuid.NullUUID{UUID: newAccount.ParentID, Valid: newAccount.ParentID != uuid.Nil},
+		})
+		if err != nil {
+			return fmt.Errorf("error creating account: %w", err)
+		}
+
+		err = qtx.CreateAccountBalance(ctx, repo.CreateAccountBalanceParams{
+			AccountID: account.ID,
+			Currency:  account.Currency,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating balance of account %s: %w", account.ID, err)
+		}
+
+		return nil
+	})
+
+	return account, err
+}
+
+func (l *LedgerService) GetAccount(ctx context.Context, id uuid.UUID) (repo.Account, error) {
+	account, err := l.store.GetAccount(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Account{}, fmt.Errorf("%w: %s", ErrAccountNotFound, id)
+		}
+		return repo.Account{}, fmt.Errorf("error fetching account %s: %w", id, err)
+	}
+
+	return account, nil
+}
+
+// UpdateAccountMetadata merges the given keys into the account metadata,
+// existing keys not present in the patch are kept.
+func (l *LedgerService) UpdateAccountMetadata(ctx context.Context, id uuid.UUID, patch []byte) (repo.Account, error) {
+	metadata, err := accountMetadata(patch)
+	if err != nil {
+		return repo.Account{}, err
+	}
+
+	account, err := l.store.UpdateAccountMetadata(ctx, repo.UpdateAccountMetadataParams{
+		Metadata: metadata,
+		ID:       id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Account{}, fmt.Errorf("%w: %s", ErrAccountNotFound, id)
+		}
+		return repo.Account{}, fmt.Errorf("error updating metadata of account %s: %w", id, err)
+	}
+
+	return account, nil
+}
+
+// UpdateBalancePolicy changes how far postings can take the account balance.
+// It only applies to postings from now on, a balance already below the new
+// floor is left as is.
+func (l *LedgerService) UpdateBalancePolicy(ctx context.Context, id uuid.UUID, policy string, overdraftLimit int64) (repo.Account, error) {
+	if err := validateBalancePolicy(policy, overdraftLimit); err != nil {
+		return repo.Account{}, err
+	}
+
+	account, err := l.store.UpdateAccountBalancePolicy(ctx, repo.UpdateAccountBalancePolicyParams{
+		ID:             id,
+		BalancePolicy:  policy,
+		OverdraftLimit: overdraftLimit,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Account{}, fmt.Errorf("%w: %s", ErrAccountNotFound, id)
+		}
+		return repo.Account{}, fmt.Errorf("error updating balance policy of account %s: %w", id, err)
+	}
+
+	return account, nil
+}
+
+// CloseAccount closes an account with a zero balance. The balance row is
+// locked so no posting can land between the check and the close. The account
+// row only takes a no key update lock, which leaves the key share locks of
+// foreign keys pointing at it, like new entries and holds, unblocked.
+func (l *LedgerService) CloseAccount(ctx context.Context, id uuid.UUID) (repo.Account, error) {
+	var account repo.Account
+
+	err := l.runInTx(ctx, func(qtx *repo.Queries) error {
+		current, err := qtx.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrAccountNotFound, id)
+			}
+			return fmt.Errorf("error fetching account %s: %w", id, err)
+		}
+
+		if current.Status == domain.AccountStatusClosed {
+			return fmt.Errorf("%w: %s", ErrAccountClosed, id)
+		}
+
+		if _, err := lockAccounts(ctx, qtx, []uuid.UUID{id}); err != nil {
+			return err
+		}
+
+		balances, err := qtx.GetAccountBalances(ctx, []uuid.UUID{id})
+		if err != nil {
+			return fmt.Errorf("error fetching balance of account %s: %w", id, err)
+		}
+		for _, balance := range balances {
+			if balance.Balance != 0 || balance.Pending != 0 {
+				return fmt.Errorf("%w: %s holds %d with %d pending", ErrAccountHasBalance, id, balance.Balance, balance.Pending)
+			}
+		}
+
+		account, err = qtx.CloseAccount(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error closing account %s: %w", id, err)
+		}
+
+		return nil
+	})
+
+	return account, err
+}
+
+func accountMetadata(raw []byte) ([]byte, error) {
+	if len(raw) == 0 {
+		return []byte("{}"), nil
+	}
+
+	var metadata map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &metadata); err != nil || metadata == nil {
+		return nil, fmt.Errorf("%w: metadata must be a JSON object", ErrInvalidAccount)
+	}
+
+	return raw, nil
+}
+
+func validateBalancePolicy(policy string, overdraftLimit int64) error {
+	switch policy {
+	case domain.BalancePolicyNonNegative, domain.BalancePolicyUnlimited:
+		if overdraftLimit != 0 {
+			return fmt.Errorf("%w: overdraft limit only applies to the %s policy", ErrInvalidAccount, domain.BalancePolicyOverdraft)
+		}
+	case domain.BalancePolicyOverdraft:
+		if overdraftLimit <= 0 {
+			return fmt.Errorf("%w: overdraft limit must be positive", ErrInvalidAccount)
+		}
+	default:
+		return fmt.Errorf("%w: unknown balance policy %q", ErrInvalidAccount, policy)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+)
+
+func TestAccountMetadata(t *testing.T) {
+	metadata, err := accountMetadata(nil)
+	if err != nil || string(metadata) != "{}" {
+		t.Errorf("Expected empty metadata to default to {}, got %s and %v", metadata, err)
+	}
+
+	for _, raw := range []string{`[1]`, `"text"`, `null`, `{`} {
+		if _, err := accountMetadata([]byte(raw)); !errors.Is(err, ErrInvalidAccount) {
+			t.Errorf("Expected ErrInvalidAccount for %s, got %v", raw, err)
+		}
+	}
+
+	if _, err := accountMetadata([]byte(`{"kyc": "done"}`)); err != nil {
+		t.Errorf("Expected a JSON object to be accepted, got %v", err)
+	}
+}
+
+func TestValidateBalancePolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		limit   int64
+		wantErr bool
+	}{
+		{policy: domain.BalancePolicyNonNegative},
+		{policy: domain.BalancePolicyUnlimited},
+		{policy: domain.BalancePolicyOverdraft, limit: 5000},
+		{policy: domain.BalancePolicyOverdraft, wantErr: true},
+		{policy: domain.BalancePolicyNonNegative, limit: 5000, wantErr: true},
+		{policy: "whatever", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := validateBalancePolicy(tt.policy, tt.limit)
+		if tt.wantErr && !errors.Is(err, ErrInvalidAccount) {
+			t.Errorf("Expected ErrInvalidAccount for %s/%d, got %v", tt.policy, tt.limit, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("Expected %s/%d to be valid, got %v", tt.policy, tt.limit, err)
+		}
+	}
+}
+
+func TestCheckFunds(t *testing.T) {
+	pool, carol := SystemPoolUSD, uuid.New()
+	accounts := []repo.Account{
+		{ID: alice, BalancePolicy: domain.BalancePolicyNonNegative},
+		{ID: bob, BalancePolicy: domain.BalancePolicyOverdraft, OverdraftLimit: 500},
+		{ID: pool, BalancePolicy: domain.BalancePolicyUnlimited},
+		{ID: carol, BalancePolicy: domain.BalancePolicyOverdraft, OverdraftLimit: 500},
+	}
+	balances := map[uuid.UUID]int64{alice: 1000, bob: 100, pool: 0, carol: -400}
+
+	tests := []struct {
+		name    string
+		debits  map[uuid.UUID]int64
+		wantErr bool
+	}{
+		{name: "within balance", debits: map[uuid.UUID]int64{alice: 1000}},
+		{name: "below zero", debits: map[uuid.UUID]int64{alice: 1001}, wantErr: true},
+		{name: "within overdraft", debits: map[uuid.UUID]int64{bob: 600}},
+		{name: "past overdraft", debits: map[uuid.UUID]int64{bob: 601}, wantErr: true},
+		{name: "unlimited", debits: map[uuid.UUID]int64{pool: 1_000_000}},
+		{name: "huge debit on an overdrawn account", debits: map[uuid.UUID]int64{carol: math.MaxInt64}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFunds(balances, tt.debits, accounts)
+			if tt.wantErr && !errors.Is(err, ErrNotEnoughFunds) {
+				t.Fatalf("Expected ErrNotEnoughFunds, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/merkle"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultAttestationInterval = time.Hour
+
+	// Days are only attested once this long has passed since they closed,
+	// so that links still committing across midnight make it into their own
+	// day. One that commits later goes to the next day attested
+	attestationSettle = 5 * time.Minute
+
+	// Attestations cover one UTC day
+	attestationPeriod = 24 * time.Hour
+)
+
+var (
+	ErrAttestationNotFound error = errors.New("no attestation for the day")
+	ErrProofUnavailable    error = errors.New("transaction is not attested yet")
+	ErrAttestationMismatch error = errors.New("ledger no longer matches its attested root")
+)
+
+// RunAttestations attests every closed day on each tick of the configured
+// interval until the context is done. Errors are logged and retried on the
+// next tick.
+func (l *LedgerService) RunAttestations(ctx context.Context) {
+	interval := defaultAttestationInterval
+	if l.cfg.ATTESTATION_INTERVAL_SECONDS > 0 {
+		interval = time.Duration(l.cfg.ATTESTATION_INTERVAL_SECONDS) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			attested, err := l.AttestLedger(ctx)
+			if err != nil {
+				slog.Error("error attesting the ledger",
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+			if attested > 0 {
+				slog.Info("ledger days attested", slog.Int("days", attested))
+			}
+		}
+	}
+}
+
+// AttestLedger stores the Merkle root of every closed UTC day with linked
+// transactions that has no attestation yet, and returns how many it stored.
+// Each attestation covers the chain_seq range right after the previous one,
+// up to the last link of its day. Links are appended under the chain lock,
+// so the committed ones always form a prefix of the chain and a link that
+// commits after its day was attested lands in the next range instead of
+// being left out. Attestations are never rewritten, a second instance racing
+// on the same day computes the same root and is ignored.
+func (l *LedgerService) AttestLedger(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-attestationSettle).UTC().Truncate(attestationPeriod)
+
+	days, err := l.store.GetUnattestedDays(ctx, pgtype.Timestamptz{Time: cutoff, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("error fetching days to attest: %w", err)
+	}
+
+	attested, err := l.store.GetLastAttestedChainSeq(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching the last attested link: %w", err)
+	}
+
+	for i, d := range days {
+		last, err := l.store.GetLastChainSeqBefore(ctx, repo.GetLastChainSeqBeforeParams{
+			AfterSeq:   attested,
+			BeforeTime: pgtype.Timestamptz{Time: d.Time.Add(attestationPeriod), Valid: true},
+		})
+		if err != nil {
+			return i, fmt.Errorf("error fetching the last link of %s: %w", d.Time.Format(time.DateOnly), err)
+		}
+
+		tree, links, _, err := l.rangeTree(ctx, attested+1, last)
+		if err != nil {
+			return i, err
+		}
+
+		err = l.store.CreateAttestation(ctx, repo.CreateAttestationParams{
+			Day:           d,
+			Root:          tree.Root(),
+			LeafCount:     int64(tree.Len()),
+			FirstChainSeq: links[0].ChainSeq.Int64,
+			LastChainSeq:  links[len(links)-1].ChainSeq.Int64,
+		})
+		if err != nil {
+			return i, fmt.Errorf("error storing attestation of %s: %w", d.Time.Format(time.DateOnly), err)
+		}
+		attested = last
+	}
+
+	return len(days), nil
+}
+
+// GetAttestation returns the root attested for a UTC day
+func (l *LedgerService) GetAttestation(ctx context.Context, d time.Time) (domain.Attestation, error) {
+	row, err := l.store.GetAttestation(ctx, pgtype.Date{Time: d, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Attestation{}, fmt.Errorf("%w: %s", ErrAttestationNotFound, d.Format(time.DateOnly))
+		}
+		return domain.Attestation{}, fmt.Errorf("error fetching attestation of %s: %w", d.Format(time.DateOnly), err)
+	}
+
+	return newAttestation(row), nil
+}
+
+// GetInclusionProof rebuilds the tree of the attestation covering the
+// chain_seq of a transaction and returns the path from its leaf to the
+// attested root. A rebuilt root that differs from the attested one means rows
+// of that range were changed after the attestation, and no proof is handed
+// out.
+func (l *LedgerService) GetInclusionProof(ctx context.Context, id uuid.UUID) (domain.InclusionProof, error) {
+	header, err := l.store.GetTransaction(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.InclusionProof{}, fmt.Errorf("%w: %s", ErrTransactionNotFound, id)
+		}
+		return domain.InclusionProof{}, fmt.Errorf("error fetching transaction %s: %w", id, err)
+	}
+	if !header.ChainSeq.Valid {
+		return domain.InclusionProof{}, fmt.Errorf("%w: %s is not linked into the hash chain", ErrProofUnavailable, id)
+	}
+
+	row, err := l.store.GetAttestationByChainSeq(ctx, header.ChainSeq.Int64)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.InclusionProof{}, fmt.Errorf("%w: %s is not attested", ErrProofUnavailable, id)
+	}
+	if err != nil {
+		return domain.InclusionProof{}, fmt.Errorf("error fetching the attestation of %s: %w", id, err)
+	}
+	attestation := newAttestation(row)
+	day := attestation.Day.Format(time.DateOnly)
+
+	tree, links, contents, err := l.rangeTree(ctx, attestation.FirstChainSeq, attestation.LastChainSeq)
+	if err != nil {
+		return domain.InclusionProof{}, err
+	}
+	if !bytes.Equal(tree.Root(), attestation.Root) {
+		return domain.InclusionProof{}, fmt.Errorf("%w: %s", ErrAttestationMismatch, day)
+	}
+
+	index := -1
+	for i, link := range links {
+		if link.ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return domain.InclusionProof{}, fmt.Errorf("%w: %s is missing from %s", ErrAttestationMismatch, id, day)
+	}
+
+	path, err := tree.Proof(index)
+	if err != nil {
+		return domain.InclusionProof{}, err
+	}
+	leaf, err := tree.Leaf(index)
+	if err != nil {
+		return domain.InclusionProof{}, err
+	}
+
+	return domain.InclusionProof{
+		TransactionID: id,
+		Day:           attestation.Day,
+		Root:          attestation.Root,
+		LeafCount:     attestation.LeafCount,
+		Index:         index,
+		LeafData:      contents[index],
+		Leaf:          leaf,
+		Path:          path,
+	}, nil
+}
+
+// rangeTree builds the Merkle tree over the canonical content of the
+// transactions linked from chain_seq first to last, in chain order.
+func (l *LedgerService) rangeTree(ctx context.Context, first, last int64) (*merkle.Tree, []repo.Transaction, [][]byte, error) {
+	links, err := l.store.GetLinkedTransactions(ctx, repo.GetLinkedTransactionsParams{FromSeq: first, ToSeq: last})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error fetching links %d to %d: %w", first, last, err)
+	}
+
+	entries := make(map[uuid.UUID][]repo.GetEntriesByTransactionIDsRow, len(links))
+	for start := 0; start < len(links); start += chainBatchSize {
+		batch := links[start:min(start+chainBatchSize, len(links))]
+		ids := make([]uuid.UUID, len(batch))
+		for i, link := range batch {
+			ids[i] = link.ID
+		}
+
+		rows, err := l.store.GetEntriesByTransactionIDs(ctx, ids)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error fetching entries of links %d to %d: %w", first, last, err)
+		}
+		for _, row := range rows {
+			entries[row.TransactionID] = append(entries[row.TransactionID], row)
+		}
+	}
+
+	contents := make([][]byte, len(links))
+	for i, link := range links {
+		contents[i], err = transactionContent(link, entries[link.ID])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	tree, err := merkle.New(contents)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error building the tree of links %d to %d: %w", first, last, err)
+	}
+
+	return tree, links, contents, nil
+}
+
+func newAttestation(row repo.LedgerAttestation) domain.Attestation {
+	return domain.Attestation{
+		Day:           row.Day.Time,
+		Root:          row.Root,
+		LeafCount:     row.LeafCount,
+		FirstChainSeq: row.FirstChainSeq,
+		LastChainSeq:  row.LastChainSeq,
+		CreatedAt:     row.CreatedAt.Time,
+	}
+}
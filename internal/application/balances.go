@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// VerifyBalances recomputes every balance from the entries and reports the
+// accounts whose materialized balance drifted away from the ledger.
+func (l *LedgerService) VerifyBalances(ctx context.Context) ([]domain.BalanceDrift, error) {
+	rows, err := l.store.GetBalanceDrift(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error computing balance drift: %w", err)
+	}
+
+	drifts := make([]domain.BalanceDrift, len(rows))
+	for i, row := range rows {
+		drifts[i] = domain.BalanceDrift{
+			AccountID:    row.AccountID,
+			Currency:     string(row.Currency),
+			Materialized: row.Materialized,
+			Computed:     row.Computed,
+		}
+	}
+
+	return drifts, nil
+}
+
+// maxBalanceLookup caps how many accounts a batched balance lookup can ask for
+const maxBalanceLookup = 100
+
+// GetAccountBalance returns the posted, pending and available balance of a
+// single account.
+func (l *LedgerService) GetAccountBalance(ctx context.Context, id uuid.UUID) (domain.AccountBalance, error) {
+	balances, err := l.GetAccountBalances(ctx, []uuid.UUID{id})
+	if err != nil {
+		return domain.AccountBalance{}, err
+	}
+	return balances[0], nil
+}
+
+// GetAccountBalances returns the balances of the given accounts in the order
+// they were asked for. Every account must exist.
+func (l *LedgerService) GetAccountBalances(ctx context.Context, ids []uuid.UUID) ([]domain.AccountBalance, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: no account ids given", ErrInvalidAccount)
+	}
+	if len(ids) > maxBalanceLookup {
+		return nil, fmt.Errorf("%w: at most %d accounts per lookup", ErrInvalidAccount, maxBalanceLookup)
+	}
+
+	rows, err := l.store.GetAccountBalances(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching account balances: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]repo.GetAccountBalancesRow, len(rows))
+	for _, row := range rows {
+		byID[row.AccountID] = row
+	}
+
+	balances := make([]domain.AccountBalance, len(ids))
+	for i, id := range ids {
+		row, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, id)
+		}
+		balances[i] = domain.AccountBalance{
+			AccountID: row.AccountID,
+			Currency:  string(row.Currency),
+			Posted:    row.Balance,
+			Pending:   row.Pending,
+			Available: row.Balance - row.Pending,
+		}
+	}
+
+	return balances, nil
+}
+
+// GetAccountBalanceAsOf sums the entries of an account that were effective at
+// the given instant. Entries are picked by their effective date, so backdated
+// postings recorded later still count. The sum starts from the latest
+// snapshot taken before that instant and only reads the entries around it.
+// Holds are not historical and the whole posted balance is reported as
+// available.
+func (l *LedgerService) GetAccountBalanceAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (domain.AccountBalance, error) {
+	row, err := l.store.GetAccountBalanceAsOf(ctx, repo.GetAccountBalanceAsOfParams{
+		AccountID: id,
+		AsOf:      pgtype.Timestamptz{Time: asOf, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.AccountBalance{}, fmt.Errorf("%w: %s", ErrAccountNotFound, id)
+		}
+		return domain.AccountBalance{}, fmt.Errorf("error computing balance of %s as of %s: %w", id, asOf, err)
+	}
+
+	return domain.AccountBalance{
+		AccountID: id,
+		Currency:  string(row.Currency),
+		Posted:    row.Balance,
+		Available: row.Balance,
+		AsOf:      asOf,
+	}, nil
+}
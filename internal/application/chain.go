@@ -0,0 +1,316 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// chainBatchSize caps how many links are linked or verified per round trip
+	chainBatchSize = 500
+
+	defaultChainInterval = time.Second
+)
+
+// chainLink is the position of a transaction in the hash chain
+type chainLink struct {
+	Hash     []byte
+	PrevHash []byte
+	ChainSeq int64
+}
+
+// chainEntry and chainContent are the canonical form of a transaction that
+// gets hashed. Only fields that never change after posting are covered, the
+// status moves when the transaction is reversed. Amounts are the exact stored
+// NUMERIC with trailing zeros trimmed: integral ones encode like an int64,
+// while a fraction edited in outside the service changes the hash even when
+// it rounds back to the same minor units.
+type chainEntry struct {
+	ID          uuid.UUID       `json:"id"`
+	AccountID   uuid.UUID       `json:"account_id"`
+	Amount      json.Number     `json:"amount"`
+	Currency    string          `json:"currency"`
+	Metadata    json.RawMessage `json:"metadata"`
+	CreatedAt   string          `json:"created_at"`
+	EffectiveAt string          `json:"effective_at"`
+}
+
+type chainContent struct {
+	ID                    uuid.UUID       `json:"id"`
+	ExternalID            *string         `json:"external_id"`
+	Description           *string         `json:"description"`
+	CreatedBy             *string         `json:"created_by"`
+	CreatedAt             string          `json:"created_at"`
+	ReversesTransactionID *uuid.UUID      `json:"reverses_transaction_id"`
+	Metadata              json.RawMessage `json:"metadata"`
+	Entries               []chainEntry    `json:"entries"`
+}
+
+// transactionHash is sha256(prev || canonical JSON of the transaction and its
+// entries). The first link has no prev.
+func transactionHash(prev []byte, header repo.Transaction, entries []repo.GetEntriesByTransactionIDsRow) ([]byte, error) {
+	canonical, err := transactionContent(header, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	hash.Write(prev)
+	hash.Write(canonical)
+	return hash.Sum(nil), nil
+}
+
+// transactionContent encodes the canonical JSON of a transaction and its
+// entries, sorted by id.
+func transactionContent(header repo.Transaction, entries []repo.GetEntriesByTransactionIDsRow) ([]byte, error) {
+	content := chainContent{
+		ID:        header.ID,
+		CreatedAt: chainTime(header.CreatedAt.Time),
+		Metadata:  header.Metadata,
+		Entries:   make([]chainEntry, len(entries)),
+	}
+	if header.ExternalID.Valid {
+		content.ExternalID = &header.ExternalID.String
+	}
+	if header.Description.Valid {
+		content.Description = &header.Description.String
+	}
+	if header.CreatedBy.Valid {
+		content.CreatedBy = &header.CreatedBy.String
+	}
+	if header.ReversesTransactionID.Valid {
+		content.ReversesTransactionID = &header.ReversesTransactionID.UUID
+	}
+
+	for i, entry := range entries {
+		content.Entries[i] = chainEntry{
+			ID:          entry.ID,
+			AccountID:   entry.AccountID,
+			Amount:      json.Number(entry.Amount),
+			Currency:    string(entry.Currency),
+			Metadata:    entry.Metadata,
+			CreatedAt:   chainTime(entry.CreatedAt.Time),
+			EffectiveAt: chainTime(entry.EffectiveAt.Time),
+		}
+	}
+	slices.SortFunc(content.Entries, func(a, b chainEntry) int {
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+
+	canonical, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding transaction %s for hashing: %w", header.ID, err)
+	}
+
+	return canonical, nil
+}
+
+func chainTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// RunHashChain links the posted transactions into the hash chain on every
+// tick of the configured interval until the context is done. Postings do not
+// link themselves: appending takes a lock on the whole chain, and holding it
+// inside every posting, on top of its account locks, would serialize all the
+// writes to the ledger. Errors are logged and retried on the next tick.
+func (l *LedgerService) RunHashChain(ctx context.Context) {
+	ticker := time.NewTicker(l.chainInterval())
+	defer ticker.Stop()
+
+	for {
+		if err := l.LinkUnchainedTransactions(ctx); err != nil {
+			slog.Error("error linking transactions into the hash chain",
+				slog.String("error", err.Error()),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *LedgerService) chainInterval() time.Duration {
+	if l.cfg.CHAIN_INTERVAL_SECONDS > 0 {
+		return time.Duration(l.cfg.CHAIN_INTERVAL_SECONDS) * time.Second
+	}
+	return defaultChainInterval
+}
+
+// appendToChain links a transaction after the current head. The caller must
+// hold the hash chain lock.
+func appendToChain(ctx context.Context, qtx *repo.Queries, id uuid.UUID) (chainLink, error) {
+	head, err := qtx.GetHashChainHead(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return chainLink{}, fmt.Errorf("error fetching the hash chain head: %w", err)
+	}
+
+	header, err := qtx.GetTransaction(ctx, id)
+	if err != nil {
+		return chainLink{}, fmt.Errorf("error fetching transaction %s: %w", id, err)
+	}
+	entries, err := qtx.GetEntriesByTransactionIDs(ctx, []uuid.UUID{id})
+	if err != nil {
+		return chainLink{}, fmt.Errorf("error fetching entries of transaction %s: %w", id, err)
+	}
+
+	hash, err := transactionHash(head.Hash, header, entries)
+	if err != nil {
+		return chainLink{}, err
+	}
+
+	link := chainLink{Hash: hash, PrevHash: head.Hash, ChainSeq: head.ChainSeq + 1}
+	err = qtx.LinkTransaction(ctx, repo.LinkTransactionParams{
+		ID:       id,
+		Hash:     link.Hash,
+		PrevHash: link.PrevHash,
+		ChainSeq: link.ChainSeq,
+	})
+	if err != nil {
+		return chainLink{}, fmt.Errorf("error linking transaction %s: %w", id, err)
+	}
+
+	return link, nil
+}
+
+// LinkUnchainedTransactions appends every settled transaction that is not
+// linked yet, oldest first, in batches under the hash chain lock.
+func (l *LedgerService) LinkUnchainedTransactions(ctx context.Context) error {
+	linked := 0
+	for {
+		var ids []uuid.UUID
+		err := l.runInTx(ctx, func(qtx *repo.Queries) error {
+			if err := qtx.LockHashChain(ctx); err != nil {
+				return fmt.Errorf("error locking the hash chain: %w", err)
+			}
+
+			var err error
+			ids, err = qtx.GetUnchainedTransactionIDs(ctx, chainBatchSize)
+			if err != nil {
+				return fmt.Errorf("error fetching unchained transactions: %w", err)
+			}
+
+			for _, id := range ids {
+				if _, err := appendToChain(ctx, qtx, id); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		linked += len(ids)
+		if len(ids) < chainBatchSize {
+			break
+		}
+	}
+
+	if linked > 0 {
+		slog.Debug("transactions linked into the hash chain", slog.Int("transactions", linked))
+	}
+	return nil
+}
+
+// VerifyHashChain walks the chain from its first link, recomputing every
+// hash, and stops at the first link that does not verify. Transactions the
+// appender has not reached yet do not make the chain invalid, only the ones
+// still unlinked well after they could have committed are reported.
+func (l *LedgerService) VerifyHashChain(ctx context.Context) (domain.ChainVerification, error) {
+	var result domain.ChainVerification
+
+	for {
+		links, err := l.store.GetHashChain(ctx, repo.GetHashChainParams{
+			AfterSeq: result.HeadSeq,
+			PageSize: chainBatchSize,
+		})
+		if err != nil {
+			return domain.ChainVerification{}, fmt.Errorf("error fetching the hash chain after %d: %w", result.HeadSeq, err)
+		}
+		if len(links) == 0 {
+			break
+		}
+
+		ids := make([]uuid.UUID, len(links))
+		for i, link := range links {
+			ids[i] = link.ID
+		}
+		rows, err := l.store.GetEntriesByTransactionIDs(ctx, ids)
+		if err != nil {
+			return domain.ChainVerification{}, fmt.Errorf("error fetching entries of the hash chain: %w", err)
+		}
+		entries := make(map[uuid.UUID][]repo.GetEntriesByTransactionIDsRow, len(links))
+		for _, row := range rows {
+			entries[row.TransactionID] = append(entries[row.TransactionID], row)
+		}
+
+		for _, link := range links {
+			reason, err := checkLink(result.HeadSeq+1, result.HeadHash, link, entries[link.ID])
+			if err != nil {
+				return domain.ChainVerification{}, err
+			}
+			if reason != "" {
+				result.Broken = &domain.BrokenLink{
+					ChainSeq:      link.ChainSeq.Int64,
+					TransactionID: link.ID,
+					Reason:        reason,
+				}
+				return result, nil
+			}
+
+			result.Checked++
+			result.HeadSeq = link.ChainSeq.Int64
+			result.HeadHash = link.Hash
+		}
+	}
+
+	// A transaction commits within txTimeout of its start and is linked on
+	// one of the next ticks after that
+	grace := txTimeout + 2*l.chainInterval()
+	unchained, err := l.store.CountUnchainedTransactions(ctx, pgtype.Timestamptz{Time: time.Now().Add(-grace), Valid: true})
+	if err != nil {
+		return domain.ChainVerification{}, fmt.Errorf("error counting unchained transactions: %w", err)
+	}
+	result.Unchained = unchained
+	result.Valid = result.Broken == nil
+
+	return result, nil
+}
+
+// checkLink returns why a link does not follow the previous one, or an empty
+// reason when it verifies.
+func checkLink(seq int64, prev []byte, link repo.Transaction, entries []repo.GetEntriesByTransactionIDsRow) (string, error) {
+	if link.ChainSeq.Int64 != seq {
+		return domain.BrokenLinkMissing, nil
+	}
+	if !bytes.Equal(link.PrevHash, prev) {
+		return domain.BrokenLinkPrevHash, nil
+	}
+
+	hash, err := transactionHash(prev, link, entries)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(link.Hash, hash) {
+		return domain.BrokenLinkContent, nil
+	}
+
+	return "", nil
+}
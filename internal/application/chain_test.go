@@ -0,0 +1,104 @@
+package application
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func chainFixture() (repo.Transaction, []repo.GetEntriesByTransactionIDsRow) {
+	now := pgtype.Timestamptz{Time: time.Date(2025, 12, 28, 10, 0, 0, 123000, time.UTC), Valid: true}
+	header := repo.Transaction{
+		ID:         uuid.New(),
+		ExternalID: pgtype.Text{String: "key", Valid: true},
+		Status:     domain.StatusPosted,
+		CreatedAt:  now,
+		Metadata:   []byte(`{"order": "42"}`),
+	}
+	entries := []repo.GetEntriesByTransactionIDsRow{
+		{ID: uuid.New(), TransactionID: header.ID, AccountID: alice, Amount: "-100", Currency: "USD", Metadata: []byte(`{}`), CreatedAt: now, EffectiveAt: now},
+		{ID: uuid.New(), TransactionID: header.ID, AccountID: bob, Amount: "100", Currency: "USD", Metadata: []byte(`{}`), CreatedAt: now, EffectiveAt: now},
+	}
+	return header, entries
+}
+
+func TestTransactionHash(t *testing.T) {
+	header, entries := chainFixture()
+	hash, err := transactionHash(nil, header, entries)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	reordered := []repo.GetEntriesByTransactionIDsRow{entries[1], entries[0]}
+	header.Status = domain.StatusReversed
+	if again, _ := transactionHash(nil, header, reordered); !bytes.Equal(hash, again) {
+		t.Error("Expected the hash to ignore entry order and status changes")
+	}
+
+	if chained, _ := transactionHash(hash, header, entries); bytes.Equal(hash, chained) {
+		t.Error("Expected the previous hash to change the hash")
+	}
+
+	edited := append([]repo.GetEntriesByTransactionIDsRow{}, entries...)
+	edited[1].Amount = "1000"
+	if tampered, _ := transactionHash(nil, header, edited); bytes.Equal(hash, tampered) {
+		t.Error("Expected an edited entry amount to change the hash")
+	}
+
+	// Both legs still round to the same minor units and sum to zero
+	fractional := append([]repo.GetEntriesByTransactionIDsRow{}, entries...)
+	fractional[0].Amount, fractional[1].Amount = "-100.4", "100.4"
+	if tampered, _ := transactionHash(nil, header, fractional); bytes.Equal(hash, tampered) {
+		t.Error("Expected a fractional entry amount to change the hash")
+	}
+}
+
+func TestTransactionContent_IntegralAmount(t *testing.T) {
+	header, entries := chainFixture()
+	content, err := transactionContent(header, entries)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Links hashed before amounts were read exactly must still verify
+	if !bytes.Contains(content, []byte(`"amount":100,`)) || !bytes.Contains(content, []byte(`"amount":-100,`)) {
+		t.Errorf("Expected integral amounts to encode as integers, got %s", content)
+	}
+}
+
+func TestCheckLink(t *testing.T) {
+	header, entries := chainFixture()
+	prev := bytes.Repeat([]byte{1}, 32)
+	hash, _ := transactionHash(prev, header, entries)
+	header.Hash, header.PrevHash, header.ChainSeq = hash, prev, pgtype.Int8{Int64: 2, Valid: true}
+
+	tests := []struct {
+		name    string
+		seq     int64
+		prev    []byte
+		entries []repo.GetEntriesByTransactionIDsRow
+		reason  string
+	}{
+		{name: "valid link", seq: 2, prev: prev, entries: entries},
+		{name: "deleted link before", seq: 1, prev: prev, entries: entries, reason: domain.BrokenLinkMissing},
+		{name: "previous hash rewritten", seq: 2, prev: bytes.Repeat([]byte{2}, 32), entries: entries, reason: domain.BrokenLinkPrevHash},
+		{name: "entry deleted", seq: 2, prev: prev, entries: entries[:1], reason: domain.BrokenLinkContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := checkLink(tt.seq, tt.prev, header, tt.entries)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if reason != tt.reason {
+				t.Errorf("Expected reason %q, got %q", tt.reason, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Fixed group accounts every customer wallet rolls up into
+var (
+	CustomerWalletsUSD = uuid.MustParse("00000000-0000-0000-0000-000000000007")
+	CustomerWalletsBRL = uuid.MustParse("00000000-0000-0000-0000-000000000008")
+)
+
+// normalBalance returns the side an account class carries its balance on:
+// debit for what the house owns or spends, credit for what it owes, its
+// equity and what it earns.
+func normalBalance(class repo.AccountClass) (string, error) {
+	switch class {
+	case repo.AccountClassAsset, repo.AccountClassExpense:
+		return domain.NormalBalanceDebit, nil
+	case repo.AccountClassLiability, repo.AccountClassEquity, repo.AccountClassRevenue:
+		return domain.NormalBalanceCredit, nil
+	}
+	return "", fmt.Errorf("%w: unknown account class %q", ErrInvalidAccount, class)
+}
+
+// SetAccountParent moves an account under another one of the same class and
+// currency, or detaches it when parent is uuid.Nil. Moves are serialized by
+// a lock on the whole tree, so two of them can not each pass the ancestor
+// check and close a cycle between them.
+func (l *LedgerService) SetAccountParent(ctx context.Context, id, parent uuid.UUID) (repo.Account, error) {
+	var account repo.Account
+	err := l.runInTx(ctx, func(qtx *repo.Queries) error {
+		if err := qtx.LockAccountTree(ctx); err != nil {
+			return fmt.Errorf("error locking the account tree: %w", err)
+		}
+
+		current, err := qtx.GetAccount(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrAccountNotFound, id)
+			}
+			return fmt.Errorf("error fetching account %s: %w", id, err)
+		}
+
+		if parent != uuid.Nil {
+			if err := validateParent(ctx, qtx, current.AccountClass, string(current.Currency), parent); err != nil {
+				return err
+			}
+
+			// The new parent must not sit below the account itself
+			subtree, err := qtx.GetAccountTree(ctx, id)
+			if err != nil {
+				return fmt.Errorf("error fetching accounts below %s: %w", id, err)
+			}
+			for _, node := range subtree {
+				if node.ID == parent {
+					return fmt.Errorf("%w: %s can not be its own ancestor", ErrInvalidAccount, id)
+				}
+			}
+		}
+
+		account, err = qtx.UpdateAccountParent(ctx, repo.UpdateAccountParentParams{
+			ID:       id,
+			ParentID: uuid.NullUUID{UUID: parent, Valid: parent != uuid.Nil},
+		})
+		if err != nil {
+			return fmt.Errorf("error updating parent of account %s: %w", id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return repo.Account{}, err
+	}
+
+	return account, nil
+}
+
+// GetAccountRollup returns the account with every account below it, each
+// carrying the total of its own balance and the balances of its children.
+func (l *LedgerService) GetAccountRollup(ctx context.Context, id uuid.UUID) (*domain.AccountNode, error) {
+	rows, err := l.store.GetAccountTree(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching accounts below %s: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, id)
+	}
+
+	return buildAccountTree(rows), nil
+}
+
+// validateParent checks that an account can roll up into parent. Rollups
+// only add up balances of the same class and currency.
+func validateParent(ctx context.Context, qtx *repo.Queries, class repo.AccountClass, currency string, parent uuid.UUID) error {
+	account, err := qtx.GetAccount(ctx, parent)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: parent %s does not exist", ErrInvalidAccount, parent)
+		}
+		return fmt.Errorf("error fetching account %s: %w", parent, err)
+	}
+
+	switch {
+	case account.Status == domain.AccountStatusClosed:
+		return fmt.Errorf("%w: parent %s is closed", ErrInvalidAccount, parent)
+	case account.AccountClass != class:
+		return fmt.Errorf("%w: parent %s is %s, not %s", ErrInvalidAccount, parent, account.AccountClass, class)
+	case string(account.Currency) != currency:
+		return fmt.Errorf("%w: parent %s holds %s, not %s", ErrInvalidAccount, parent, account.Currency, currency)
+	}
+
+	return nil
+}
+
+// buildAccountTree links the rows of GetAccountTree, the root first, and
+// rolls the balances up from the leaves.
+func buildAccountTree(rows []repo.GetAccountTreeRow) *domain.AccountNode {
+	nodes := make(map[uuid.UUID]*domain.AccountNode, len(rows))
+	for _, row := range rows {
+		nodes[row.ID] = &domain.AccountNode{
+			ID:            row.ID,
+			ParentID:      row.ParentID.UUID,
+			Name:          row.Name,
+			Currency:      string(row.Currency),
+			Class:         string(row.AccountClass),
+			NormalBalance: row.NormalBalance,
+			Balance:       row.Balance,
+			Total:         row.Balance,
+		}
+	}
+
+	root := nodes[rows[0].ID]
+	for _, row := range rows[1:] {
+		node := nodes[row.ID]
+		if parent, ok := nodes[node.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	// Rows come ordered by depth, so walking them backwards adds every
+	// child into its parent after the child got its own children
+	for i := len(rows) - 1; i > 0; i-- {
+		node := nodes[rows[i].ID]
+		if parent, ok := nodes[node.ParentID]; ok {
+			parent.Total += node.Total
+		}
+	}
+
+	return root
+}
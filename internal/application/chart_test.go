@@ -0,0 +1,54 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+)
+
+func TestNormalBalance(t *testing.T) {
+	tests := map[repo.AccountClass]string{
+		repo.AccountClassAsset:     domain.NormalBalanceDebit,
+		repo.AccountClassExpense:   domain.NormalBalanceDebit,
+		repo.AccountClassLiability: domain.NormalBalanceCredit,
+		repo.AccountClassEquity:    domain.NormalBalanceCredit,
+		repo.AccountClassRevenue:   domain.NormalBalanceCredit,
+	}
+	for class, want := range tests {
+		if got, err := normalBalance(class); err != nil || got != want {
+			t.Errorf("Expected %s to be %s, got %s and %v", class, want, got, err)
+		}
+	}
+
+	if _, err := normalBalance("income"); !errors.Is(err, ErrInvalidAccount) {
+		t.Errorf("Expected ErrInvalidAccount for an unknown class, got %v", err)
+	}
+}
+
+func TestBuildAccountTree(t *testing.T) {
+	wallets := CustomerWalletsUSD
+	merchants := uuid.New()
+	shop := uuid.New()
+	parent := func(id uuid.UUID) uuid.NullUUID { return uuid.NullUUID{UUID: id, Valid: true} }
+
+	rows := []repo.GetAccountTreeRow{
+		{ID: wallets, Name: "Customer Wallets USD", Depth: 0, Balance: 0},
+		{ID: alice, ParentID: parent(wallets), Name: "Alice", Depth: 1, Balance: 1000},
+		{ID: merchants, ParentID: parent(wallets), Name: "Merchants", Depth: 1, Balance: 5},
+		{ID: shop, ParentID: parent(merchants), Name: "Shop", Depth: 2, Balance: 250},
+	}
+
+	root := buildAccountTree(rows)
+	if root.ID != wallets || len(root.Children) != 2 {
+		t.Fatalf("Expected the group account with 2 children, got %s with %d", root.ID, len(root.Children))
+	}
+	if root.Total != 1255 {
+		t.Errorf("Expected the root to roll up 1255, got %d", root.Total)
+	}
+	if got := root.Children[1]; got.ID != merchants || got.Total != 255 {
+		t.Errorf("Expected merchants to roll up 255, got %s with %d", got.ID, got.Total)
+	}
+}
@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/google/uuid"
+)
+
+var ErrRateUnavailable error = errors.New("no conversion rate available for the currency pair")
+
+// fxPrice is a conversion rate as the house applies it: Rate is the provider
+// Mid rate with SpreadBps taken off, both rounded the way they are shown.
+type fxPrice struct {
+	Mid       fx.Rate
+	Rate      fx.Rate
+	SpreadBps int32
+}
+
+// price fetches the mid rate of a pair and applies the spread for converting
+// amount.
+func (l *LedgerService) price(ctx context.Context, pair fx.Pair, amount int64) (fxPrice, error) {
+	mid, err := l.rates.Rate(ctx, pair)
+	if err != nil {
+		slog.Error("error checking currency",
+			slog.String("error", err.Error()),
+		)
+
+		return fxPrice{}, fmt.Errorf("%w: %v", ErrRateUnavailable, err)
+	}
+
+	spread, err := l.spreadFor(ctx, pair, amount)
+	if err != nil {
+		return fxPrice{}, err
+	}
+
+	mid, err = roundRate(mid)
+	if err != nil {
+		return fxPrice{}, err
+	}
+	rate, err := applySpread(mid, spread)
+	if err != nil {
+		return fxPrice{}, err
+	}
+
+	return fxPrice{Mid: mid, Rate: rate, SpreadBps: spread}, nil
+}
+
+// conversionLegs routes a cross-currency transfer through the system pools.
+// The sender pays the source pool and the target pool pays out the amount at
+// the mid rate, so the pools always trade at the mid rate. The receiver gets
+// the amount at the applied rate and the difference, the spread, goes to the
+// FX revenue account of the target currency.
+func conversionLegs(transaction *domain.Transaction, source, target Currency, price fxPrice) ([]domain.EntryRequest, error) {
+	if price.Mid.Value == nil || price.Mid.Value.Sign() <= 0 || price.Rate.Value == nil || price.Rate.Value.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %s%s", ErrRateUnavailable, source, target)
+	}
+
+	atMid, err := convertAmount(transaction.Value, price.Mid.Value)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := convertAmount(transaction.Value, price.Rate.Value)
+	if err != nil {
+		return nil, err
+	}
+	if converted <= 0 {
+		return nil, fmt.Errorf("%w: %d %s converts to nothing", ErrInvalidTransaction, transaction.Value, source)
+	}
+	margin := atMid - converted
+	if margin < 0 {
+		return nil, fmt.Errorf("%w: rate %s is above the mid rate %s", ErrInvalidTransaction, price.Rate.Decimal(), price.Mid.Decimal())
+	}
+
+	fields := map[string]string{
+		"fx_pair":        string(source) + string(target),
+		"fx_mid_rate":    price.Mid.Decimal(),
+		"fx_rate":        price.Rate.Decimal(),
+		"fx_spread_bps":  strconv.Itoa(int(price.SpreadBps)),
+		"fx_rate_source": price.Rate.Source,
+	}
+	if transaction.QuoteID != uuid.Nil {
+		fields["fx_quote_id"] = transaction.QuoteID.String()
+	}
+
+	metadata, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding fx metadata: %w", err)
+	}
+
+	legs := []domain.EntryRequest{
+		{AccountID: transaction.From, Amount: -transaction.Value, Currency: string(source), Metadata: metadata},
+		{AccountID: systemPools[source], Amount: transaction.Value, Currency: string(source), Metadata: metadata},
+		{AccountID: systemPools[target], Amount: -atMid, Currency: string(target), Metadata: metadata},
+		{AccountID: transaction.To, Amount: converted, Currency: string(target), Metadata: metadata},
+	}
+
+	if margin > 0 {
+		fields["type"] = "fx_spread"
+		spreadMetadata, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding fx metadata: %w", err)
+		}
+
+		legs = append(legs, domain.EntryRequest{
+			AccountID: fxRevenueAccounts[target],
+			Amount:    margin,
+			Currency:  string(target),
+			Metadata:  spreadMetadata,
+		})
+	}
+
+	return legs, nil
+}
+
+// convertAmount applies a rate to an amount in minor units. The result is
+// truncated, the fraction of a cent stays in the pool.
+func convertAmount(amount int64, rate *big.Rat) (int64, error) {
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+	result := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("%w: %d at %s overflows", ErrInvalidTransaction, amount, rate.RatString())
+	}
+
+	return result.Int64(), nil
+}
+
+// applySpread takes the spread off a rate, rounded the way it is shown.
+func applySpread(mid fx.Rate, spreadBps int32) (fx.Rate, error) {
+	margin := big.NewRat(int64(bpsDenominator-spreadBps), bpsDenominator)
+	return roundRate(fx.Rate{
+		Pair:   mid.Pair,
+		Value:  new(big.Rat).Mul(mid.Value, margin),
+		Source: mid.Source,
+		AsOf:   mid.AsOf,
+	})
+}
+
+// roundRate rounds a rate to its decimal form, the precision it is stored
+// and shown with.
+func roundRate(rate fx.Rate) (fx.Rate, error) {
+	if rate.Value == nil {
+		return fx.Rate{}, fmt.Errorf("%w: %s", ErrRateUnavailable, rate.Pair)
+	}
+
+	value, err := fx.ParseRate(rate.Decimal())
+	if err != nil {
+		return fx.Rate{}, fmt.Errorf("%w: %v", ErrRateUnavailable, err)
+	}
+	rate.Value = value
+	return rate, nil
+}
@@ -0,0 +1,100 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+)
+
+func TestConvertAmount(t *testing.T) {
+	tests := []struct {
+		amount int64
+		rate   string
+		want   int64
+	}{
+		{amount: 10000, rate: "5.33", want: 53300},
+		{amount: 1, rate: "0.1876", want: 0},
+		{amount: 53300, rate: "0.1876", want: 9999},
+		{amount: 12345, rate: "1", want: 12345},
+	}
+
+	for _, tt := range tests {
+		rate, _ := fx.ParseRate(tt.rate)
+		got, err := convertAmount(tt.amount, rate)
+		if err != nil {
+			t.Fatalf("convertAmount(%d, %s) returned error: %v", tt.amount, tt.rate, err)
+		}
+		if got != tt.want {
+			t.Errorf("convertAmount(%d, %s) = %d, want %d", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestConversionLegs(t *testing.T) {
+	transaction := &domain.Transaction{From: alice, To: bob, Currency: "USD", Value: 10000}
+	mid := fx.Rate{Pair: fx.Pair{Base: "USD", Quote: "BRL"}, Value: big.NewRat(533, 100), Source: "static"}
+
+	legs, err := conversionLegs(transaction, CurrencyUSD, CurrencyBRL, fxPrice{Mid: mid, Rate: mid})
+	if err != nil {
+		t.Fatalf("conversionLegs returned error: %v", err)
+	}
+
+	if err := validatePosting(&domain.Posting{Entries: legs}); err != nil {
+		t.Fatalf("Expected balanced legs, got %v", err)
+	}
+
+	if legs[1].AccountID != SystemPoolUSD || legs[2].AccountID != SystemPoolBRL {
+		t.Errorf("Expected the conversion to go through the system pools")
+	}
+	if legs[3].AccountID != bob || legs[3].Amount != 53300 {
+		t.Errorf("Expected bob to receive 53300 BRL, got %d to %s", legs[3].Amount, legs[3].AccountID)
+	}
+	if len(legs) != 4 {
+		t.Errorf("Expected no spread leg without a spread, got %d legs", len(legs))
+	}
+
+	if _, err := conversionLegs(transaction, CurrencyUSD, CurrencyBRL, fxPrice{}); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("Expected ErrRateUnavailable without rates, got %v", err)
+	}
+}
+
+func TestConversionLegsSpread(t *testing.T) {
+	transaction := &domain.Transaction{From: alice, To: bob, Currency: "USD", Value: 10000}
+	mid := fx.Rate{Pair: fx.Pair{Base: "USD", Quote: "BRL"}, Value: big.NewRat(533, 100), Source: "static"}
+	rate, err := applySpread(mid, 100)
+	if err != nil {
+		t.Fatalf("applySpread returned error: %v", err)
+	}
+
+	legs, err := conversionLegs(transaction, CurrencyUSD, CurrencyBRL, fxPrice{Mid: mid, Rate: rate, SpreadBps: 100})
+	if err != nil {
+		t.Fatalf("conversionLegs returned error: %v", err)
+	}
+	if err := validatePosting(&domain.Posting{Entries: legs}); err != nil {
+		t.Fatalf("Expected balanced legs, got %v", err)
+	}
+
+	// 100 USD at the 5.33 mid rate leave the pool, bob gets them at 5.2767
+	// and the 0.533 BRL in between is the house's
+	if legs[2].AccountID != SystemPoolBRL || legs[2].Amount != -53300 {
+		t.Errorf("Expected the BRL pool to pay out 53300 at the mid rate, got %d", legs[2].Amount)
+	}
+	if legs[3].AccountID != bob || legs[3].Amount != 52767 {
+		t.Errorf("Expected bob to receive 52767 BRL, got %d", legs[3].Amount)
+	}
+	if len(legs) != 5 || legs[4].AccountID != SystemFXRevenueBRL || legs[4].Amount != 533 {
+		t.Fatalf("Expected 533 BRL of spread on the FX revenue account, got %+v", legs[len(legs)-1])
+	}
+
+	var metadata map[string]string
+	if err := json.Unmarshal(legs[4].Metadata, &metadata); err != nil {
+		t.Fatalf("Expected JSON metadata, got %v", err)
+	}
+	if metadata["type"] != "fx_spread" || metadata["fx_mid_rate"] != "5.33" || metadata["fx_rate"] != "5.2767" || metadata["fx_spread_bps"] != "100" {
+		t.Errorf("Expected the mid and applied rates on the spread leg, got %v", metadata)
+	}
+}
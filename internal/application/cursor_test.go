@@ -0,0 +1,40 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 6, 30, 23, 59, 59, 123456000, time.UTC)
+	id := uuid.New()
+
+	gotTime, gotID, err := decodeCursor(encodeCursor(createdAt, id))
+	if err != nil {
+		t.Fatalf("decodeCursor returned error: %v", err)
+	}
+	if !gotTime.Equal(createdAt) || gotID != id {
+		t.Errorf("Expected %s/%s, got %s/%s", createdAt, id, gotTime, gotID)
+	}
+
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", encodeCursor(createdAt, id)[:10]} {
+		if _, _, err := decodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", cursor, err)
+		}
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	if got := pageSize(0); got != defaultPageSize {
+		t.Errorf("Expected the default page size, got %d", got)
+	}
+	if got := pageSize(10_000); got != maxPageSize {
+		t.Errorf("Expected the page size to be capped at %d, got %d", maxPageSize, got)
+	}
+	if got := pageSize(20); got != 20 {
+		t.Errorf("Expected 20, got %d", got)
+	}
+}
@@ -0,0 +1,185 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+)
+
+const (
+	FeeKindPercentage = "percentage"
+	FeeKindFlat       = "flat"
+	FeeKindTiered     = "tiered"
+)
+
+type feeTier struct {
+	// UpTo is the inclusive upper bound of the tier, nil for the last one
+	UpTo *int64 `json:"up_to"`
+	Bps  int64  `json:"bps"`
+	Flat int64  `json:"flat"`
+}
+
+// FeeRule is a single fee schedule row ready to be applied to an amount.
+type FeeRule struct {
+	ID    uuid.UUID
+	Kind  string
+	Bps   int64
+	Flat  int64
+	Min   *int64
+	Max   *int64
+	Tiers []feeTier
+}
+
+// Calculate returns the fee in minor units for the given amount. Percentage
+// fees are expressed in basis points and rounded half up.
+func (r FeeRule) Calculate(amount int64) int64 {
+	var fee int64
+
+	switch r.Kind {
+	case FeeKindPercentage:
+		fee = basisPoints(amount, r.Bps)
+	case FeeKindFlat:
+		fee = r.Flat
+	case FeeKindTiered:
+		for _, tier := range r.Tiers {
+			if tier.UpTo == nil || amount <= *tier.UpTo {
+				var ok bool
+				fee, ok = addAmounts(basisPoints(amount, tier.Bps), tier.Flat)
+				if !ok {
+					// Beyond any entry, the posting rejects it
+					fee = math.MaxInt64
+				}
+				break
+			}
+		}
+	}
+
+	if r.Min != nil && fee < *r.Min {
+		fee = *r.Min
+	}
+	if r.Max != nil && fee > *r.Max {
+		fee = *r.Max
+	}
+
+	return fee
+}
+
+// basisPoints returns bps of amount rounded half up. The product is taken as
+// a big.Int, it overflows int64 well within the entry cap.
+func basisPoints(amount, bps int64) int64 {
+	product := new(big.Int).Mul(big.NewInt(amount), big.NewInt(bps))
+	product.Add(product, big.NewInt(bpsDenominator/2))
+	return product.Quo(product, big.NewInt(bpsDenominator)).Int64()
+}
+
+func newFeeRule(schedule repo.FeeSchedule) (FeeRule, error) {
+	rule := FeeRule{
+		ID:   schedule.ID,
+		Kind: schedule.Kind,
+		Bps:  schedule.Bps,
+		Flat: schedule.FlatAmount,
+	}
+
+	if schedule.MinAmount.Valid {
+		rule.Min = &schedule.MinAmount.Int64
+	}
+	if schedule.MaxAmount.Valid {
+		rule.Max = &schedule.MaxAmount.Int64
+	}
+
+	if schedule.Kind == FeeKindTiered {
+		if err := json.Unmarshal(schedule.Tiers, &rule.Tiers); err != nil {
+			return FeeRule{}, fmt.Errorf("error decoding tiers of fee schedule %s: %w", schedule.ID, err)
+		}
+	}
+
+	if err := validFeeAmounts(rule.Bps, rule.Flat); err != nil {
+		return FeeRule{}, fmt.Errorf("fee schedule %s %w", schedule.ID, err)
+	}
+	if (rule.Min != nil && *rule.Min < 0) || (rule.Max != nil && *rule.Max < 0) {
+		return FeeRule{}, fmt.Errorf("fee schedule %s has a negative cap", schedule.ID)
+	}
+	if rule.Min != nil && rule.Max != nil && *rule.Min > *rule.Max {
+		return FeeRule{}, fmt.Errorf("fee schedule %s has a min of %d over its max of %d", schedule.ID, *rule.Min, *rule.Max)
+	}
+	// Tiers are matched in order, so their bounds must go up and only the
+	// last one may be open
+	for i, tier := range rule.Tiers {
+		if err := validFeeAmounts(tier.Bps, tier.Flat); err != nil {
+			return FeeRule{}, fmt.Errorf("fee schedule %s tier %d %w", schedule.ID, i, err)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := rule.Tiers[i-1].UpTo
+		if previous == nil {
+			return FeeRule{}, fmt.Errorf("fee schedule %s has a tier after the open one", schedule.ID)
+		}
+		if tier.UpTo != nil && *tier.UpTo <= *previous {
+			return FeeRule{}, fmt.Errorf("fee schedule %s has tier bounds out of order at %d", schedule.ID, *tier.UpTo)
+		}
+	}
+
+	return rule, nil
+}
+
+// validFeeAmounts checks the rate and the flat amount of a fee schedule or of
+// one of its tiers, neither may be negative nor charge more than the amount
+// or an entry can hold.
+func validFeeAmounts(bps, flat int64) error {
+	if bps < 0 || bps > bpsDenominator {
+		return fmt.Errorf("has a rate of %d bps", bps)
+	}
+	if flat < 0 || flat > maxEntryAmount {
+		return fmt.Errorf("has a flat amount of %d", flat)
+	}
+	return nil
+}
+
+// feeLegs loads the active fee schedules for the transaction type and returns
+// one debit/credit pair per fee, charged to the payer and credited to the
+// fee revenue account of the currency.
+func (l *LedgerService) feeLegs(ctx context.Context, transactionType string, payer uuid.UUID, currency Currency, amount int64) ([]domain.EntryRequest, error) {
+	schedules, err := l.store.GetActiveFeeSchedules(ctx, repo.GetActiveFeeSchedulesParams{
+		TransactionType: transactionType,
+		Currency:        repo.Currency(currency),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching fee schedules: %w", err)
+	}
+
+	var legs []domain.EntryRequest
+	for _, schedule := range schedules {
+		rule, err := newFeeRule(schedule)
+		if err != nil {
+			return nil, err
+		}
+
+		fee := rule.Calculate(amount)
+		if fee <= 0 {
+			continue
+		}
+
+		metadata, err := json.Marshal(map[string]string{
+			"type":            "fee",
+			"fee_schedule_id": rule.ID.String(),
+			"fee_kind":        rule.Kind,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error encoding fee metadata: %w", err)
+		}
+
+		legs = append(legs,
+			domain.EntryRequest{AccountID: payer, Amount: -fee, Currency: string(currency), Metadata: metadata},
+			domain.EntryRequest{AccountID: feeRevenueAccounts[currency], Amount: fee, Currency: string(currency), Metadata: metadata},
+		)
+	}
+
+	return legs, nil
+}
@@ -0,0 +1,95 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func ptr(v int64) *int64 { return &v }
+
+func TestFeeRule_Calculate(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   FeeRule
+		amount int64
+		want   int64
+	}{
+		{name: "percentage 1%", rule: FeeRule{Kind: FeeKindPercentage, Bps: 100}, amount: 100000, want: 1000},
+		{name: "percentage rounds half up", rule: FeeRule{Kind: FeeKindPercentage, Bps: 100}, amount: 150, want: 2},
+		{name: "flat", rule: FeeRule{Kind: FeeKindFlat, Flat: 250}, amount: 100000, want: 250},
+		{name: "percentage with min", rule: FeeRule{Kind: FeeKindPercentage, Bps: 100, Min: ptr(50)}, amount: 1000, want: 50},
+		{name: "percentage with max", rule: FeeRule{Kind: FeeKindPercentage, Bps: 100, Max: ptr(500)}, amount: 100000, want: 500},
+		{name: "percentage of a large amount", rule: FeeRule{Kind: FeeKindPercentage, Bps: 100}, amount: 100_000_000_000_000_000, want: 1_000_000_000_000_000},
+		{
+			name: "tiered first tier",
+			rule: FeeRule{Kind: FeeKindTiered, Tiers: []feeTier{
+				{UpTo: ptr(10000), Bps: 200},
+				{Bps: 100, Flat: 10},
+			}},
+			amount: 10000,
+			want:   200,
+		},
+		{
+			name: "tiered open ended tier",
+			rule: FeeRule{Kind: FeeKindTiered, Tiers: []feeTier{
+				{UpTo: ptr(10000), Bps: 200},
+				{Bps: 100, Flat: 10},
+			}},
+			amount: 20000,
+			want:   210,
+		},
+		{name: "unknown kind charges nothing", rule: FeeRule{Kind: "other", Bps: 100}, amount: 100000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Calculate(tt.amount); got != tt.want {
+				t.Errorf("Calculate(%d) = %d, want %d", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFeeRule(t *testing.T) {
+	rule, err := newFeeRule(repo.FeeSchedule{
+		Kind:      FeeKindTiered,
+		MaxAmount: pgtype.Int8{Int64: 300, Valid: true},
+		Tiers:     []byte(`[{"up_to": 5000, "bps": 300}, {"bps": 150}]`),
+	})
+	if err != nil {
+		t.Fatalf("newFeeRule returned error: %v", err)
+	}
+
+	if rule.Min != nil || rule.Max == nil || *rule.Max != 300 {
+		t.Errorf("Expected only a max cap of 300, got min=%v max=%v", rule.Min, rule.Max)
+	}
+	if len(rule.Tiers) != 2 || rule.Tiers[1].UpTo != nil {
+		t.Fatalf("Expected two tiers with an open ended last one, got %+v", rule.Tiers)
+	}
+	if got := rule.Calculate(100000); got != 300 {
+		t.Errorf("Expected the cap to apply, got %d", got)
+	}
+	invalid := []struct {
+		name     string
+		schedule repo.FeeSchedule
+	}{
+		{name: "negative bps", schedule: repo.FeeSchedule{Kind: FeeKindPercentage, Bps: -100}},
+		{name: "negative flat", schedule: repo.FeeSchedule{Kind: FeeKindFlat, FlatAmount: -250}},
+		{name: "min over max", schedule: repo.FeeSchedule{
+			Kind:      FeeKindPercentage,
+			Bps:       100,
+			MinAmount: pgtype.Int8{Int64: 500, Valid: true},
+			MaxAmount: pgtype.Int8{Int64: 300, Valid: true},
+		}},
+		{name: "tier over the whole amount", schedule: repo.FeeSchedule{Kind: FeeKindTiered, Tiers: []byte(`[{"up_to": null, "bps": 10001}]`)}},
+		{name: "tiers out of order", schedule: repo.FeeSchedule{Kind: FeeKindTiered, Tiers: []byte(`[{"up_to": 100000, "bps": 100}, {"up_to": 5000, "bps": 300}]`)}},
+		{name: "tier after the open one", schedule: repo.FeeSchedule{Kind: FeeKindTiered, Tiers: []byte(`[{"up_to": null, "bps": 150}, {"up_to": 5000, "bps": 300}]`)}},
+	}
+	for _, tt := range invalid {
+		if _, err := newFeeRule(tt.schedule); err == nil {
+			t.Errorf("Expected a schedule with %s to be rejected", tt.name)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultHoldTTL = 7 * 24 * time.Hour
+	maxHoldTTL     = 30 * 24 * time.Hour
+
+	holdExpiryInterval = 30 * time.Second
+	holdExpiryBatch    = 100
+)
+
+var (
+	ErrInvalidHold    error = errors.New("invalid hold")
+	ErrHoldNotFound   error = errors.New("hold not found")
+	ErrHoldNotPending error = errors.New("hold is no longer pending")
+	ErrHoldExpired    error = errors.New("hold has expired")
+)
+
+// CreateHold reserves funds on an account. It opens a pending transaction
+// with no entries and raises the pending amount of the account, so the
+// available balance drops while the posted balance stays untouched.
+func (l *LedgerService) CreateHold(ctx context.Context, hold *domain.NewHold) (*domain.Hold, error) {
+	if err := validateHold(hold); err != nil {
+		return nil, err
+	}
+
+	ttl := hold.TTL
+	if ttl == 0 {
+		ttl = defaultHoldTTL
+		if l.cfg.HOLD_TTL_SECONDS > 0 {
+			ttl = time.Duration(l.cfg.HOLD_TTL_SECONDS) * time.Second
+		}
+	}
+
+	externalID := idempotencyKey(hold.IdempotencyKey)
+	if externalID.Valid {
+		replay, err := l.replayHold(ctx, externalID)
+		if err != nil {
+			return nil, err
+		}
+		if replay != nil {
+			return replay, nil
+		}
+	}
+
+	var created repo.Hold
+	err := l.runInTx(ctx, func(qtx *repo.Queries) error {
+		var err error
+		created, err = createHoldInTx(ctx, qtx, hold, externalID, ttl)
+		return err
+	})
+	if errors.Is(err, errDuplicateExternalID) {
+		return l.replayHold(ctx, externalID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newHold(created), nil
+}
+
+func createHoldInTx(ctx context.Context, qtx *repo.Queries, hold *domain.NewHold, externalID pgtype.Text, ttl time.Duration) (repo.Hold, error) {
+	// The legs the capture will post, checked up front against the accounts
+	posting := &domain.Posting{Entries: holdLegs(hold.AccountID, hold.CounterpartyID, hold.Currency, hold.Amount, uuid.Nil)}
+	ids := postingAccounts(posting)
+
+	balances, err := lockAccounts(ctx, qtx, ids)
+	if err != nil {
+		return repo.Hold{}, err
+	}
+
+	accounts, err := qtx.GetAccountsByIDs(ctx, ids)
+	if err != nil {
+		return repo.Hold{}, fmt.Errorf("error fetching hold accounts: %w", err)
+	}
+	if err := validateAccounts(posting, accounts); err != nil {
+		return repo.Hold{}, err
+	}
+	debits, err := netDebits(posting)
+	if err != nil {
+		return repo.Hold{}, err
+	}
+	if err := checkFunds(balances, debits, accounts); err != nil {
+		return repo.Hold{}, err
+	}
+
+	metadata := hold.Metadata
+	if len(metadata) == 0 {
+		metadata = []byte("{}")
+	}
+
+	header, err := qtx.CreateTransaction(ctx, repo.CreateTransactionParams{
+		ID:          uuid.New(),
+		ExternalID:  externalID,
+		Description: pgtype.Text{String: hold.Description, Valid: hold.Description != ""},
+		Status:      domain.StatusPending,
+		CreatedBy:   pgtype.Text{String: hold.CreatedBy, Valid: hold.CreatedBy != ""},
+		Metadata:    metadata,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repo.Hold{}, errDuplicateExternalID
+	}
+	if err != nil {
+		return repo.Hold{}, fmt.Errorf("error creating pending transaction: %w", err)
+	}
+
+	created, err := qtx.CreateHold(ctx, repo.CreateHoldParams{
+		ID:             uuid.New(),
+		TransactionID:  header.ID,
+		AccountID:      hold.AccountID,
+		CounterpartyID: hold.CounterpartyID,
+		Currency:       repo.Currency(hold.Currency),
+		Amount:         hold.Amount,
+		ExpiresAt:      pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return repo.Hold{}, fmt.Errorf("error creating hold: %w", err)
+	}
+
+	err = qtx.ApplyPendingDelta(ctx, repo.ApplyPendingDeltaParams{
+		AccountID: created.AccountID,
+		Currency:  created.Currency,
+		Pending:   created.Amount,
+	})
+	if err != nil {
+		return repo.Hold{}, fmt.Errorf("error reserving funds on account %s: %w", created.AccountID, err)
+	}
+
+	return created, nil
+}
+
+// GetHold returns a hold by its id.
+func (l *LedgerService) GetHold(ctx context.Context, id uuid.UUID) (*domain.Hold, error) {
+	hold, err := l.store.GetHold(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrHoldNotFound, id)
+		}
+		return nil, fmt.Errorf("error fetching hold %s: %w", id, err)
+	}
+
+	return newHold(hold), nil
+}
+
+// CaptureHold releases the reservation and posts the real entries, for the
+// whole held amount when amount is zero or for part of it. Whatever is not
+// captured goes back to the available balance.
+func (l *LedgerService) CaptureHold(ctx context.Context, id uuid.UUID, amount int64) (*domain.TransactionResult, error) {
+	if amount < 0 {
+		return nil, fmt.Errorf("%w: capture amount must not be negative", ErrInvalidHold)
+	}
+
+	var result *domain.TransactionResult
+	err := l.runInTx(ctx, func(qtx *repo.Queries) error {
+		hold, err := lockPendingHold(ctx, qtx, id)
+		if err != nil {
+			return err
+		}
+		if !hold.ExpiresAt.Time.After(time.Now()) {
+			return fmt.Errorf("%w: %s", ErrHoldExpired, id)
+		}
+
+		captured := amount
+		if captured == 0 {
+			captured = hold.Amount
+		}
+		if captured > hold.Amount {
+			return fmt.Errorf("%w: %d requested but only %d is held", ErrInvalidHold, captured, hold.Amount)
+		}
+
+		// Both balances are locked in the usual order before the release
+		// touches the held one, so the capture can not deadlock a posting
+		if _, err := lockAccounts(ctx, qtx, []uuid.UUID{hold.AccountID, hold.CounterpartyID}); err != nil {
+			return err
+		}
+
+		// The reservation has to be gone before the posting checks the funds
+		if err := releaseHold(ctx, qtx, hold); err != nil {
+			return err
+		}
+
+		posting := &domain.Posting{
+			Entries:              holdLegs(hold.AccountID, hold.CounterpartyID, string(hold.Currency), captured, hold.ID),
+			PendingTransactionID: hold.TransactionID,
+		}
+		if err := validatePosting(posting); err != nil {
+			return err
+		}
+
+		result, err = l.postInTx(ctx, qtx, posting, pgtype.Text{})
+		if err != nil {
+			return err
+		}
+
+		_, err = qtx.UpdateHoldStatus(ctx, repo.UpdateHoldStatusParams{
+			ID:             hold.ID,
+			Status:         domain.HoldStatusCaptured,
+			CapturedAmount: captured,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating hold %s: %w", hold.ID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// VoidHold releases a pending hold without moving any money.
+func (l *LedgerService) VoidHold(ctx context.Context, id uuid.UUID) (*domain.Hold, error) {
+	var voided repo.Hold
+	err := l.runInTx(ctx, func(qtx *repo.Queries) error {
+		hold, err := lockPendingHold(ctx, qtx, id)
+		if err != nil {
+			return err
+		}
+
+		voided, err = cancelHold(ctx, qtx, hold, domain.HoldStatusVoided)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newHold(voided), nil
+}
+
+// RunHoldExpiry expires the holds past their TTL on every tick until the
+// context is done.
+func (l *LedgerService) RunHoldExpiry(ctx context.Context) {
+	ticker := time.NewTicker(holdExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := l.ExpireHolds(ctx)
+			if err != nil {
+				slog.Error("error expiring holds",
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+			if expired > 0 {
+				slog.Info("holds expired", slog.Int("holds", expired))
+			}
+		}
+	}
+}
+
+// ExpireHolds releases a batch of pending holds past their TTL, each in its
+// own transaction, and returns how many were expired.
+func (l *LedgerService) ExpireHolds(ctx context.Context) (int, error) {
+	ids, err := l.store.GetExpiredHoldIDs(ctx, holdExpiryBatch)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching expired holds: %w", err)
+	}
+
+	expired := 0
+	for _, id := range ids {
+		err := l.runInTx(ctx, func(qtx *repo.Queries) error {
+			hold, err := lockPendingHold(ctx, qtx, id)
+			if err != nil {
+				return err
+			}
+
+			_, err = cancelHold(ctx, qtx, hold, domain.HoldStatusExpired)
+			return err
+		})
+		// Captured or voided since the batch was fetched
+		if errors.Is(err, ErrHoldNotPending) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+func lockPendingHold(ctx context.Context, qtx *repo.Queries, id uuid.UUID) (repo.Hold, error) {
+	hold, err := qtx.GetHoldForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Hold{}, fmt.Errorf("%w: %s", ErrHoldNotFound, id)
+		}
+		return repo.Hold{}, fmt.Errorf("error fetching hold %s: %w", id, err)
+	}
+
+	if hold.Status != domain.HoldStatusPending {
+		return repo.Hold{}, fmt.Errorf("%w: %s is %s", ErrHoldNotPending, id, hold.Status)
+	}
+
+	return hold, nil
+}
+
+// cancelHold releases the reservation of a hold that will never be captured
+// and voids its pending transaction.
+func cancelHold(ctx context.Context, qtx *repo.Queries, hold repo.Hold, status string) (repo.Hold, error) {
+	if err := releaseHold(ctx, qtx, hold); err != nil {
+		return repo.Hold{}, err
+	}
+
+	err := qtx.UpdateTransactionStatus(ctx, repo.UpdateTransactionStatusParams{ID: hold.TransactionID, Status: domain.StatusVoided})
+	if err != nil {
+		return repo.Hold{}, fmt.Errorf("error voiding transaction %s: %w", hold.TransactionID, err)
+	}
+
+	updated, err := qtx.UpdateHoldStatus(ctx, repo.UpdateHoldStatusParams{ID: hold.ID, Status: status})
+	if err != nil {
+		return repo.Hold{}, fmt.Errorf("error updating hold %s: %w", hold.ID, err)
+	}
+
+	return updated, nil
+}
+
+// releaseHold gives the held amount back to the available balance. The
+// balance row is locked first like any posting would.
+func releaseHold(ctx context.Context, qtx *repo.Queries, hold repo.Hold) error {
+	if _, err := lockAccounts(ctx, qtx, []uuid.UUID{hold.AccountID}); err != nil {
+		return err
+	}
+
+	err := qtx.ApplyPendingDelta(ctx, repo.ApplyPendingDeltaParams{
+		AccountID: hold.AccountID,
+		Currency:  hold.Currency,
+		Pending:   -hold.Amount,
+	})
+	if err != nil {
+		return fmt.Errorf("error releasing funds on account %s: %w", hold.AccountID, err)
+	}
+
+	return nil
+}
+
+func validateHold(hold *domain.NewHold) error {
+	switch {
+	case hold.Amount <= 0:
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidHold)
+	case hold.AccountID == uuid.Nil || hold.CounterpartyID == uuid.Nil:
+		return fmt.Errorf("%w: account and counterparty are required", ErrInvalidHold)
+	case hold.AccountID == hold.CounterpartyID:
+		return fmt.Errorf("%w: account and counterparty must differ", ErrInvalidHold)
+	case !Currency(hold.Currency).IsSupported():
+		return fmt.Errorf("%w: unsupported currency %q", ErrInvalidHold, hold.Currency)
+	case hold.TTL < 0 || hold.TTL > maxHoldTTL:
+		return fmt.Errorf("%w: ttl must be between 0 and %s", ErrInvalidHold, maxHoldTTL)
+	}
+
+	if len(hold.Metadata) > 0 {
+		var metadata map[string]json.RawMessage
+		if err := json.Unmarshal(hold.Metadata, &metadata); err != nil {
+			return fmt.Errorf("%w: metadata must be a JSON object", ErrInvalidHold)
+		}
+	}
+
+	return nil
+}
+
+// holdLegs moves amount from the held account to the counterparty, tagging
+// the entries with the hold they settle.
+func holdLegs(from, to uuid.UUID, currency string, amount int64, holdID uuid.UUID) []domain.EntryRequest {
+	var metadata []byte
+	if holdID != uuid.Nil {
+		metadata, _ = json.Marshal(map[string]string{"hold_id": holdID.String()})
+	}
+
+	return []domain.EntryRequest{
+		{AccountID: from, Amount: -amount, Currency: currency, Metadata: metadata},
+		{AccountID: to, Amount: amount, Currency: currency, Metadata: metadata},
+	}
+}
+
+func (l *LedgerService) replayHold(ctx context.Context, externalID pgtype.Text) (*domain.Hold, error) {
+	header, err := l.store.GetTransactionByExternalID(ctx, externalID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error looking up external id %s: %w", externalID.String, err)
+	}
+
+	hold, err := l.store.GetHoldByTransactionID(ctx, header.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: idempotency key %s belongs to a transaction", ErrInvalidHold, externalID.String)
+		}
+		return nil, fmt.Errorf("error fetching hold of transaction %s: %w", header.ID, err)
+	}
+
+	replay := newHold(hold)
+	replay.Replayed = true
+	return replay, nil
+}
+
+func newHold(hold repo.Hold) *domain.Hold {
+	return &domain.Hold{
+		ID:             hold.ID,
+		TransactionID:  hold.TransactionID,
+		AccountID:      hold.AccountID,
+		CounterpartyID: hold.CounterpartyID,
+		Currency:       string(hold.Currency),
+		Amount:         hold.Amount,
+		CapturedAmount: hold.CapturedAmount,
+		Status:         hold.Status,
+		ExpiresAt:      hold.ExpiresAt.Time,
+		CreatedAt:      hold.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,58 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestValidateHold(t *testing.T) {
+	valid := domain.NewHold{AccountID: alice, CounterpartyID: bob, Currency: "USD", Amount: 100}
+
+	tests := []struct {
+		name    string
+		mutate  func(h *domain.NewHold)
+		wantErr error
+	}{
+		{name: "valid", mutate: func(h *domain.NewHold) {}},
+		{name: "zero amount", mutate: func(h *domain.NewHold) { h.Amount = 0 }, wantErr: ErrInvalidHold},
+		{name: "same account", mutate: func(h *domain.NewHold) { h.CounterpartyID = alice }, wantErr: ErrInvalidHold},
+		{name: "missing counterparty", mutate: func(h *domain.NewHold) { h.CounterpartyID = uuid.Nil }, wantErr: ErrInvalidHold},
+		{name: "unsupported currency", mutate: func(h *domain.NewHold) { h.Currency = "EUR" }, wantErr: ErrInvalidHold},
+		{name: "ttl too long", mutate: func(h *domain.NewHold) { h.TTL = maxHoldTTL + time.Hour }, wantErr: ErrInvalidHold},
+		{name: "metadata not an object", mutate: func(h *domain.NewHold) { h.Metadata = []byte(`[]`) }, wantErr: ErrInvalidHold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hold := valid
+			tt.mutate(&hold)
+
+			err := validateHold(&hold)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestHoldLegs(t *testing.T) {
+	holdID := uuid.New()
+	legs := holdLegs(alice, bob, "USD", 250, holdID)
+
+	if err := validatePosting(&domain.Posting{Entries: legs}); err != nil {
+		t.Fatalf("Expected balanced hold legs, got %v", err)
+	}
+	if debits, _ := netDebits(&domain.Posting{Entries: legs}); debits[alice] != 250 {
+		t.Errorf("Expected the held account to be debited 250, got %d", debits[alice])
+	}
+	if string(legs[0].Metadata) != `{"hold_id":"`+holdID.String()+`"}` {
+		t.Errorf("Expected the entries to reference the hold, got %s", legs[0].Metadata)
+	}
+}
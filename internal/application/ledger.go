@@ -2,156 +2,163 @@ package application
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
-	"time"
 
 	"github.com/IgorGrieder/Small-Ledger/internal/cfg"
 	"github.com/IgorGrieder/Small-Ledger/internal/domain"
-	"github.com/IgorGrieder/Small-Ledger/internal/http/httpclient"
-	"github.com/IgorGrieder/Small-Ledger/internal/http/httputils"
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
 	"github.com/IgorGrieder/Small-Ledger/internal/repo"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
-	"github.com/redis/go-redis/v9"
-	"golang.org/x/sync/errgroup"
 )
 
 type LedgerService struct {
-	store      *repo.SQLStore
-	httpClient *httpclient.Client
-	cfg        *cfg.Config
-	redis      *redis.Client
-	cacheMax   time.Duration
+	store *repo.SQLStore
+	cfg   *cfg.Config
+	rates fx.RateProvider
 }
 
-type conversionRates struct {
-	USD string `json:"USD"`
-	BRL string `json:"BRL"`
-}
-
-type CurrencyResponse struct {
-	ConversionRates conversionRates `json:"conversion_rates"`
-}
+var (
+	ErrNotEnoughFunds        error = errors.New("not enough funds to proceed teh transaction")
+	ErrInvalidTransaction    error = errors.New("invalid transaction, amount must be positive and accounts must differ")
+	ErrInvalidEntry          error = errors.New("invalid entry")
+	ErrUnbalancedTransaction error = errors.New("entries must sum to zero per currency")
+	ErrAccountNotFound       error = errors.New("account not found")
+	ErrCurrencyMismatch      error = errors.New("entry currency does not match the account currency")
 
-var ErrNotEnoughFunds error = errors.New("not enough funds to proceed teh transaction")
+	errDuplicateExternalID = errors.New("a transaction with the same external id was already posted")
+)
 
-func NewLedgerService(cfg *cfg.Config, store *repo.SQLStore, redis *redis.Client, httpClient *httpclient.Client) *LedgerService {
+func NewLedgerService(cfg *cfg.Config, store *repo.SQLStore, rates fx.RateProvider) *LedgerService {
 	return &LedgerService{
-		store:      store,
-		httpClient: httpClient,
-		cfg:        cfg,
-		redis:      redis,
-		cacheMax:   1 * time.Hour,
+		store: store,
+		cfg:   cfg,
+		rates: rates,
 	}
 }
 
-func (l *LedgerService) ProcessTransaction(ctx context.Context, transaction *domain.Transaction) error {
-	_, err := l.checkCurrency(ctx, transaction)
-	if err != nil {
-		slog.Error("error checking currency",
-			slog.String("error", err.Error()),
-		)
-
-		return err
+func (l *LedgerService) ProcessTransaction(ctx context.Context, transaction *domain.Transaction) (*domain.TransactionResult, error) {
+	if transaction.Value <= 0 || transaction.From == transaction.To {
+		return nil, ErrInvalidTransaction
 	}
 
-	tx, err := l.store.CreateTx(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	// A retry of a transfer that already committed gets it back before the
+	// price, the quote or the fees are looked up again, as they may fail now
+	externalID := idempotencyKey(transaction.CorrelationId)
+	if externalID.Valid {
+		replay, err := l.replayTransaction(ctx, externalID)
+		if err != nil || replay != nil {
+			return replay, err
+		}
 	}
-	defer tx.Rollback(ctx)
-
-	qtx := l.store.WithTx(tx)
 
-	err = l.checkFunds(ctx, qtx, transaction)
+	accounts, err := l.store.GetAccountsByIDs(ctx, []uuid.UUID{transaction.From, transaction.To})
 	if err != nil {
-		slog.Error("error checking user funds",
-			slog.String("error", err.Error()),
-		)
-
-		return err
+		return nil, fmt.Errorf("error fetching transfer accounts: %w", err)
 	}
 
-	return tx.Commit(ctx)
-}
-
-func (l *LedgerService) GetAllAccounts(ctx context.Context) ([]repo.Account, error) {
-	return l.store.GetAllAccounts(ctx)
-}
-
-func (l *LedgerService) checkFunds(ctx context.Context, tx *repo.Queries, transaction *domain.Transaction) error {
-	ctxQuery, cancel := context.WithTimeout(ctx, 2*time.Second)
-	defer cancel()
-
-	var (
-		fundsFrom int64
-		fundsTo   int64
-	)
+	var from, to *repo.Account
+	for i := range accounts {
+		switch accounts[i].ID {
+		case transaction.From:
+			from = &accounts[i]
+		case transaction.To:
+			to = &accounts[i]
+		}
+	}
+	if from == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, transaction.From)
+	}
+	if to == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, transaction.To)
+	}
+	if string(from.Currency) != transaction.Currency {
+		return nil, fmt.Errorf("%w: sender holds %s, transfer is in %s", ErrCurrencyMismatch, from.Currency, transaction.Currency)
+	}
 
-	group, ctxGroup := errgroup.WithContext(ctxQuery)
+	posting := &domain.Posting{
+		IdempotencyKey: transaction.CorrelationId,
+		Description:    fmt.Sprintf("transfer from %s to %s", transaction.From, transaction.To),
+	}
 
-	group.Go(func() error {
-		return fetchFunds(tx, ctxGroup, transaction.From, "From", &fundsFrom)
-	})
+	transactionType := TransactionTypeTransfer
+	if from.Currency == to.Currency {
+		if transaction.QuoteID != uuid.Nil {
+			return nil, fmt.Errorf("%w: a quote only prices transfers between currencies", ErrInvalidQuote)
+		}
 
-	group.Go(func() error {
-		return fetchFunds(tx, ctxGroup, transaction.To, "To", &fundsTo)
-	})
+		posting.Entries = []domain.EntryRequest{
+			{AccountID: transaction.From, Amount: -transaction.Value, Currency: transaction.Currency},
+			{AccountID: transaction.To, Amount: transaction.Value, Currency: transaction.Currency},
+		}
+	} else {
+		transactionType = TransactionTypeFXTransfer
+
+		var price fxPrice
+		if transaction.QuoteID != uuid.Nil {
+			price, err = l.quotedPrice(ctx, transaction, Currency(from.Currency), Currency(to.Currency))
+			if errors.Is(err, ErrQuoteUsed) && externalID.Valid {
+				// The first attempt may have committed since the replay
+				// lookup and used the quote up
+				if replay, replayErr := l.replayTransaction(ctx, externalID); replayErr != nil || replay != nil {
+					return replay, replayErr
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			posting.QuoteID = transaction.QuoteID
+		} else {
+			price, err = l.price(ctx, fx.Pair{Base: string(from.Currency), Quote: string(to.Currency)}, transaction.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
 
-	if err := group.Wait(); err != nil {
-		return err
+		posting.Entries, err = conversionLegs(transaction, Currency(from.Currency), Currency(to.Currency), price)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if fundsFrom < transaction.Value {
-		return ErrNotEnoughFunds
+	fees, err := l.feeLegs(ctx, transactionType, transaction.From, Currency(from.Currency), transaction.Value)
+	if err != nil {
+		return nil, err
 	}
+	posting.Entries = append(posting.Entries, fees...)
 
-	return nil
+	return l.PostTransaction(ctx, posting)
 }
 
-func fetchFunds(tx *repo.Queries, ctx context.Context, userID uuid.UUID, label string, dest *int64) error {
-	funds, err := tx.GetUserFunds(ctx, userID)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("user not found for %s=%s: %w", label, userID, err)
-		}
-		return fmt.Errorf("error consulting user %s=%s: %v", label, userID, err)
-	}
-	*dest = funds
-	return nil
+func (l *LedgerService) GetAllAccounts(ctx context.Context) ([]repo.Account, error) {
+	return l.store.GetAllAccounts(ctx)
 }
 
-func (l *LedgerService) checkCurrency(ctx context.Context, transaction *domain.Transaction) (conversionRates, error) {
-	val, err := l.redis.Get(ctx, transaction.Currency).Result()
-	if err == nil {
-		var cachedRates conversionRates
-		if err := json.Unmarshal([]byte(val), &cachedRates); err == nil {
-			return cachedRates, nil
-		}
-	} else if err != redis.Nil {
-		slog.Error("error getting currency from cache", slog.String("error", err.Error()))
+// checkFunds makes sure every debited account stays within its balance
+// policy once its net debit is taken from its locked available balance.
+func checkFunds(balances map[uuid.UUID]int64, debits map[uuid.UUID]int64, accounts []repo.Account) error {
+	policies := make(map[uuid.UUID]repo.Account, len(accounts))
+	for _, account := range accounts {
+		policies[account.ID] = account
 	}
 
-	response, err := l.httpClient.Get(ctx, l.cfg.CURRENCY_URL+transaction.Currency, nil, nil)
-	if err != nil {
-		return conversionRates{}, fmt.Errorf("error checking currency %v", err)
-	}
+	for id, debit := range debits {
+		account := policies[id]
 
-	var rates CurrencyResponse
-	if err := httputils.DecodeJSONRaw(response.Body, rates); err != nil {
-		return conversionRates{}, fmt.Errorf("error decoding currency response: %w", err)
-	}
-	defer response.Body.Close()
+		var floor int64
+		switch account.BalancePolicy {
+		case domain.BalancePolicyUnlimited:
+			continue
+		case domain.BalancePolicyOverdraft:
+			floor = -account.OverdraftLimit
+		}
 
-	resultRates := conversionRates{USD: rates.ConversionRates.USD, BRL: rates.ConversionRates.BRL}
-	if jsonData, err := json.Marshal(resultRates); err == nil {
-		if err := l.redis.Set(ctx, transaction.Currency, jsonData, l.cacheMax).Err(); err != nil {
-			slog.Error("error setting currency to cache", slog.String("error", err.Error()))
+		// The headroom only overflows when it is beyond any debit
+		headroom, ok := addAmounts(balances[id], -floor)
+		if ok && debit > headroom {
+			return fmt.Errorf("%w: account %s", ErrNotEnoughFunds, id)
 		}
 	}
 
-	return resultRates, nil
+	return nil
 }
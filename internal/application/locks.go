@@ -0,0 +1,150 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	lockTimeout      = 2 * time.Second
+	txTimeout        = 15 * time.Second
+	maxTxRetries     = 3
+	txRetryBaseDelay = 50 * time.Millisecond
+	txRetryMaxJitter = 50
+)
+
+// Postgres error codes worth retrying the whole transaction for
+const (
+	pgDeadlockDetected     = "40P01"
+	pgSerializationFailure = "40001"
+	pgLockNotAvailable     = "55P03"
+)
+
+// ErrAccountBusy is returned once a transaction kept waiting over lockTimeout
+// for locks held by other transactions on every retry.
+var ErrAccountBusy error = errors.New("accounts are busy with other transactions, retry later")
+
+// lockAccounts takes the row lock of every account balance in ascending UUID
+// order and returns the available balances, posted minus held. Every posting
+// locks its accounts up front in the same order, so two postings touching the
+// same accounts queue behind each other instead of deadlocking. Accounts
+// without a balance row never got an entry and have a zero balance. Each wait
+// is bounded by the lock_timeout runInTx sets.
+func lockAccounts(ctx context.Context, tx *repo.Queries, accounts []uuid.UUID) (map[uuid.UUID]int64, error) {
+	balances := make(map[uuid.UUID]int64, len(accounts))
+
+	for _, account := range lockOrder(accounts) {
+		balance, err := tx.GetAccountBalanceForUpdate(ctx, account)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("error locking account %s: %w", account, err)
+		}
+		balances[account] = balance
+	}
+
+	return balances, nil
+}
+
+// lockOrder returns the distinct accounts sorted by their UUID bytes.
+func lockOrder(accounts []uuid.UUID) []uuid.UUID {
+	sorted := slices.Clone(accounts)
+	slices.SortFunc(sorted, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return slices.Compact(sorted)
+}
+
+// runInTx runs fn inside a DB transaction and commits it. When Postgres
+// aborts the transaction for a deadlock, a serialization failure or a lock
+// wait over lockTimeout the whole transaction is retried with a bounded
+// exponential backoff. Postgres cuts off a transaction still open after
+// txTimeout, which is what lets balance snapshots treat old enough seqs as
+// final. Lock waits are bounded in Postgres rather than by cancelling the
+// context, which would kill the connection instead of failing the statement.
+func (l *LedgerService) runInTx(ctx context.Context, fn func(qtx *repo.Queries) error) error {
+	err := withRetry(ctx, func() error {
+		tx, err := l.store.CreateTx(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback(ctx)
+
+		timeouts := fmt.Sprintf("SET LOCAL transaction_timeout = %d; SET LOCAL lock_timeout = %d",
+			txTimeout.Milliseconds(), lockTimeout.Milliseconds())
+		if _, err := tx.Exec(ctx, timeouts); err != nil {
+			return fmt.Errorf("error setting the transaction timeouts: %w", err)
+		}
+
+		if err := fn(l.store.WithTx(tx)); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+
+		return nil
+	})
+	if pgErrorCode(err) == pgLockNotAvailable {
+		return fmt.Errorf("%w: %w", ErrAccountBusy, err)
+	}
+
+	return err
+}
+
+func withRetry(ctx context.Context, fn func() error) error {
+	var err error
+
+	for i := 0; i <= maxTxRetries; i++ {
+		err = fn()
+		if err == nil || !isRetryable(err) || i == maxTxRetries {
+			return err
+		}
+
+		backoff := txRetryBaseDelay << i
+		jitter := time.Duration(rand.IntN(txRetryMaxJitter)) * time.Millisecond
+		sleepDuration := backoff + jitter
+
+		slog.Warn("Transaction aborted by the database, retrying",
+			slog.Int("attempt", i+1),
+			slog.String("sleep_duration", sleepDuration.String()),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleepDuration):
+		}
+	}
+
+	return err
+}
+
+func isRetryable(err error) bool {
+	switch pgErrorCode(err) {
+	case pgDeadlockDetected, pgSerializationFailure, pgLockNotAvailable:
+		return true
+	}
+	return false
+}
+
+// pgErrorCode returns the SQLSTATE of a Postgres error, or an empty string
+// for any other error.
+func pgErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+	return pgErr.Code
+}
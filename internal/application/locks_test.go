@@ -0,0 +1,89 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestLockOrder(t *testing.T) {
+	a := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	b := uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+	c := uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")
+
+	// A->B and B->A must lock in the very same order
+	first := lockOrder([]uuid.UUID{c, a, b, a})
+	second := lockOrder([]uuid.UUID{b, c, a})
+
+	want := []uuid.UUID{a, b, c}
+	for _, got := range [][]uuid.UUID{first, second} {
+		if len(got) != len(want) {
+			t.Fatalf("Expected %d distinct accounts, got %d", len(want), len(got))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Position %d: expected %s, got %s", i, want[i], got[i])
+			}
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	deadlock := fmt.Errorf("error locking account: %w", &pgconn.PgError{Code: pgDeadlockDetected})
+
+	attempts := 0
+	err := withRetry(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return deadlock
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	err = withRetry(context.Background(), func() error {
+		attempts++
+		return ErrNotEnoughFunds
+	})
+	if !errors.Is(err, ErrNotEnoughFunds) || attempts != 1 {
+		t.Errorf("Expected a single attempt for a business error, got %d attempts and %v", attempts, err)
+	}
+
+	attempts = 0
+	err = withRetry(context.Background(), func() error {
+		attempts++
+		return &pgconn.PgError{Code: pgSerializationFailure}
+	})
+	if !isRetryable(err) || attempts != maxTxRetries+1 {
+		t.Errorf("Expected %d attempts before giving up, got %d and %v", maxTxRetries+1, attempts, err)
+	}
+	attempts = 0
+	err = withRetry(context.Background(), func() error {
+		attempts++
+		if attempts < 2 {
+			return fmt.Errorf("error locking account: %w", &pgconn.PgError{Code: pgLockNotAvailable})
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("Expected a lock timeout to be retried, got %d attempts and %v", attempts, err)
+	}
+}
+
+func TestTxTimeoutWithinSnapshotSettle(t *testing.T) {
+	// Snapshots treat seqs handed out two settle periods ago as final, which
+	// only holds while every transaction is cut off well before that
+	if txTimeout >= snapshotSettle {
+		t.Errorf("Expected the transaction timeout %s to stay under the snapshot settle %s", txTimeout, snapshotSettle)
+	}
+}
@@ -0,0 +1,382 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxEntryAmount caps the absolute amount of a single entry, in minor units,
+// so that balances and report sums stay far from the int64 range.
+const maxEntryAmount int64 = 1_000_000_000_000_000
+
+// PostTransaction validates a multi-leg posting and writes it atomically,
+// header and entries in the same DB transaction.
+func (l *LedgerService) PostTransaction(ctx context.Context, posting *domain.Posting) (*domain.TransactionResult, error) {
+	if err := validatePosting(posting); err != nil {
+		return nil, err
+	}
+
+	externalID := idempotencyKey(posting.IdempotencyKey)
+	if externalID.Valid {
+		replay, err := l.replayTransaction(ctx, externalID)
+		if err != nil {
+			return nil, err
+		}
+		if replay != nil {
+			return replay, nil
+		}
+	}
+
+	var result *domain.TransactionResult
+	err := l.runInTx(ctx, func(qtx *repo.Queries) error {
+		var err error
+		result, err = l.postInTx(ctx, qtx, posting, externalID)
+		return err
+	})
+	if errors.Is(err, errDuplicateExternalID) {
+		// A concurrent request with the same key committed first, so hand
+		// back its result instead of failing the retry
+		return l.replayTransaction(ctx, externalID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// postInTx runs the account checks, the funds check and the inserts of an
+// already validated posting on the given pgx transaction.
+func (l *LedgerService) postInTx(ctx context.Context, qtx *repo.Queries, posting *domain.Posting, externalID pgtype.Text) (*domain.TransactionResult, error) {
+	ids := postingAccounts(posting)
+
+	// Lock first so the account checks below see any close committed while
+	// this posting was waiting for the locks
+	balances, err := lockAccounts(ctx, qtx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := qtx.GetAccountsByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching posting accounts: %w", err)
+	}
+
+	if err := validateAccounts(posting, accounts); err != nil {
+		return nil, err
+	}
+
+	debits, err := netDebits(posting)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkFunds(balances, debits, accounts)
+	if err != nil {
+		slog.Error("error checking user funds",
+			slog.String("error", err.Error()),
+		)
+
+		return nil, err
+	}
+
+	result, err := insertPosting(ctx, qtx, posting, externalID)
+	if err != nil {
+		if !errors.Is(err, errDuplicateExternalID) {
+			slog.Error("error posting transaction",
+				slog.String("error", err.Error()),
+			)
+		}
+		return nil, err
+	}
+
+	if posting.QuoteID != uuid.Nil {
+		if err := useQuote(ctx, qtx, posting.QuoteID, result.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// validatePosting checks everything that can be checked without the
+// database: every leg is well formed and the legs sum to zero per currency.
+func validatePosting(posting *domain.Posting) error {
+	if posting.EffectiveAt.After(time.Now()) {
+		return fmt.Errorf("%w: effective date is in the future", ErrInvalidTransaction)
+	}
+
+	if len(posting.Metadata) > 0 {
+		var metadata map[string]json.RawMessage
+		if err := json.Unmarshal(posting.Metadata, &metadata); err != nil {
+			return fmt.Errorf("%w: metadata must be a JSON object", ErrInvalidTransaction)
+		}
+	}
+
+	if len(posting.Entries) < 2 {
+		return fmt.Errorf("%w: a transaction needs at least two entries", ErrInvalidEntry)
+	}
+
+	sums := make(map[string]int64)
+	for i, entry := range posting.Entries {
+		if entry.AccountID == uuid.Nil {
+			return fmt.Errorf("%w: entry %d has no account", ErrInvalidEntry, i)
+		}
+		if entry.Amount == 0 {
+			return fmt.Errorf("%w: entry %d has a zero amount", ErrInvalidEntry, i)
+		}
+		if entry.Amount > maxEntryAmount || entry.Amount < -maxEntryAmount {
+			return fmt.Errorf("%w: entry %d amount exceeds %d", ErrInvalidEntry, i, maxEntryAmount)
+		}
+		if !Currency(entry.Currency).IsSupported() {
+			return fmt.Errorf("%w: entry %d has unsupported currency %q", ErrInvalidEntry, i, entry.Currency)
+		}
+		if len(entry.Metadata) > 0 {
+			var metadata map[string]json.RawMessage
+			if err := json.Unmarshal(entry.Metadata, &metadata); err != nil {
+				return fmt.Errorf("%w: entry %d metadata must be a JSON object", ErrInvalidEntry, i)
+			}
+		}
+
+		sum, ok := addAmounts(sums[entry.Currency], entry.Amount)
+		if !ok {
+			return fmt.Errorf("%w: %s entries overflow", ErrInvalidEntry, entry.Currency)
+		}
+		sums[entry.Currency] = sum
+	}
+
+	for currency, sum := range sums {
+		if sum != 0 {
+			return fmt.Errorf("%w: %s entries sum to %d", ErrUnbalancedTransaction, currency, sum)
+		}
+	}
+
+	return nil
+}
+
+// validateAccounts checks that every leg targets an existing open account
+// held in the same currency as the leg.
+func validateAccounts(posting *domain.Posting, accounts []repo.Account) error {
+	byID := make(map[uuid.UUID]repo.Account, len(accounts))
+	for _, account := range accounts {
+		byID[account.ID] = account
+	}
+
+	for _, entry := range posting.Entries {
+		account, ok := byID[entry.AccountID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrAccountNotFound, entry.AccountID)
+		}
+		if account.Status == domain.AccountStatusClosed {
+			return fmt.Errorf("%w: %s", ErrAccountClosed, account.ID)
+		}
+		if string(account.Currency) != entry.Currency {
+			return fmt.Errorf("%w: account %s holds %s, entry is in %s", ErrCurrencyMismatch, account.ID, account.Currency, entry.Currency)
+		}
+	}
+
+	return nil
+}
+
+func postingAccounts(posting *domain.Posting) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(posting.Entries))
+	ids := make([]uuid.UUID, 0, len(posting.Entries))
+	for _, entry := range posting.Entries {
+		if !seen[entry.AccountID] {
+			seen[entry.AccountID] = true
+			ids = append(ids, entry.AccountID)
+		}
+	}
+	return ids
+}
+
+// netDebits returns how much each account loses in the posting, accounts
+// that only receive money are left out.
+func netDebits(posting *domain.Posting) (map[uuid.UUID]int64, error) {
+	net := make(map[uuid.UUID]int64)
+	for _, entry := range posting.Entries {
+		sum, ok := addAmounts(net[entry.AccountID], entry.Amount)
+		if !ok {
+			return nil, fmt.Errorf("%w: entries of account %s overflow", ErrInvalidEntry, entry.AccountID)
+		}
+		net[entry.AccountID] = sum
+	}
+
+	debits := make(map[uuid.UUID]int64)
+	for account, amount := range net {
+		if amount < 0 {
+			debits[account] = -amount
+		}
+	}
+	return debits, nil
+}
+
+// addAmounts adds two amounts in minor units and reports false when the sum
+// does not fit in an int64.
+func addAmounts(a, b int64) (int64, bool) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// insertPosting writes the transaction header, its entries and the matching
+// balance updates. It must run inside the same pgx transaction that checked
+// the funds.
+func insertPosting(ctx context.Context, tx *repo.Queries, posting *domain.Posting, externalID pgtype.Text) (*domain.TransactionResult, error) {
+	header, err := postingHeader(ctx, tx, posting, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newTransactionResult(header)
+
+	for _, leg := range posting.Entries {
+		metadata := leg.Metadata
+		if len(metadata) == 0 {
+			metadata = []byte("{}")
+		}
+
+		entry, err := tx.CreateEntry(ctx, repo.CreateEntryParams{
+			ID:            uuid.New(),
+			TransactionID: header.ID,
+			AccountID:     leg.AccountID,
+			Amount:        leg.Amount,
+			Currency:      repo.Currency(leg.Currency),
+			Metadata:      metadata,
+			EffectiveAt: pgtype.Timestamptz{
+				Time:  posting.EffectiveAt,
+				Valid: !posting.EffectiveAt.IsZero(),
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating entry for account %s: %w", leg.AccountID, err)
+		}
+
+		err = tx.ApplyBalanceDelta(ctx, repo.ApplyBalanceDeltaParams{
+			AccountID:   entry.AccountID,
+			Currency:    entry.Currency,
+			Balance:     entry.Amount,
+			LastEntryID: uuid.NullUUID{UUID: entry.ID, Valid: true},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error updating balance of account %s: %w", leg.AccountID, err)
+		}
+
+		result.Entries = append(result.Entries, domainEntry(entry))
+	}
+
+	return result, nil
+}
+
+// postingHeader opens the transaction header of a posting, or moves the
+// pending header of a captured hold to posted.
+func postingHeader(ctx context.Context, tx *repo.Queries, posting *domain.Posting, externalID pgtype.Text) (repo.Transaction, error) {
+	if posting.PendingTransactionID != uuid.Nil {
+		header, err := tx.PostPendingTransaction(ctx, posting.PendingTransactionID)
+		if err != nil {
+			return repo.Transaction{}, fmt.Errorf("error posting pending transaction %s: %w", posting.PendingTransactionID, err)
+		}
+		return header, nil
+	}
+
+	metadata := posting.Metadata
+	if len(metadata) == 0 {
+		metadata = []byte("{}")
+	}
+
+	header, err := tx.CreateTransaction(ctx, repo.CreateTransactionParams{
+		ID:          uuid.New(),
+		ExternalID:  externalID,
+		Description: pgtype.Text{String: posting.Description, Valid: posting.Description != ""},
+		Status:      domain.StatusPosted,
+		CreatedBy:   pgtype.Text{String: posting.CreatedBy, Valid: posting.CreatedBy != ""},
+		ReversesTransactionID: uuid.NullUUID{
+			UUID:  posting.ReversesTransactionID,
+			Valid: posting.ReversesTransactionID != uuid.Nil,
+		},
+		Metadata: metadata,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// ON CONFLICT (external_id) DO NOTHING returns no row for duplicates
+		return repo.Transaction{}, errDuplicateExternalID
+	}
+	if err != nil {
+		return repo.Transaction{}, fmt.Errorf("error creating transaction header: %w", err)
+	}
+
+	return header, nil
+}
+
+// replayTransaction returns the already posted transaction for the given
+// idempotency key, or nil when the key has not been used yet.
+func (l *LedgerService) replayTransaction(ctx context.Context, externalID pgtype.Text) (*domain.TransactionResult, error) {
+	header, err := l.store.GetTransactionByExternalID(ctx, externalID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error looking up external id %s: %w", externalID.String, err)
+	}
+
+	entries, err := l.store.GetEntriesByTransactionID(ctx, header.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching entries for transaction %s: %w", header.ID, err)
+	}
+
+	result := newTransactionResult(header)
+	result.Replayed = true
+	for _, entry := range entries {
+		result.Entries = append(result.Entries, domainEntry(repo.CreateEntryRow(entry)))
+	}
+
+	return result, nil
+}
+
+func idempotencyKey(key uuid.UUID) pgtype.Text {
+	if key == uuid.Nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: key.String(), Valid: true}
+}
+
+func newTransactionResult(header repo.Transaction) *domain.TransactionResult {
+	return &domain.TransactionResult{
+		ID:                    header.ID,
+		ExternalID:            header.ExternalID.String,
+		Description:           header.Description.String,
+		CreatedBy:             header.CreatedBy.String,
+		ReversesTransactionID: header.ReversesTransactionID.UUID,
+		Status:                header.Status,
+		Metadata:              header.Metadata,
+		CreatedAt:             header.CreatedAt.Time,
+		Entries:               []domain.Entry{},
+		Hash:                  header.Hash,
+		PrevHash:              header.PrevHash,
+		ChainSeq:              header.ChainSeq.Int64,
+	}
+}
+
+func domainEntry(entry repo.CreateEntryRow) domain.Entry {
+	return domain.Entry{
+		ID:            entry.ID,
+		TransactionID: entry.TransactionID,
+		AccountID:     entry.AccountID,
+		Amount:        entry.Amount,
+		Currency:      string(entry.Currency),
+		Metadata:      entry.Metadata,
+		CreatedAt:     entry.CreatedAt.Time,
+		EffectiveAt:   entry.EffectiveAt.Time,
+	}
+}
@@ -0,0 +1,192 @@
+package application
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+)
+
+var (
+	alice = uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+	bob   = uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")
+)
+
+func TestValidatePosting(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []domain.EntryRequest
+		wantErr error
+	}{
+		{
+			name: "balanced single currency",
+			entries: []domain.EntryRequest{
+				{AccountID: alice, Amount: -100, Currency: "USD"},
+				{AccountID: bob, Amount: 100, Currency: "USD"},
+			},
+		},
+		{
+			name: "balanced per currency with four legs",
+			entries: []domain.EntryRequest{
+				{AccountID: alice, Amount: -100, Currency: "USD"},
+				{AccountID: SystemPoolUSD, Amount: 100, Currency: "USD"},
+				{AccountID: SystemPoolBRL, Amount: -530, Currency: "BRL"},
+				{AccountID: bob, Amount: 530, Currency: "BRL", Metadata: []byte(`{"rate":"5.30"}`)},
+			},
+		},
+		{
+			name: "balanced overall but not per currency",
+			entries: []domain.EntryRequest{
+				{AccountID: alice, Amount: -100, Currency: "USD"},
+				{AccountID: bob, Amount: 100, Currency: "BRL"},
+			},
+			wantErr: ErrUnbalancedTransaction,
+		},
+		{
+			name: "single entry",
+			entries: []domain.EntryRequest{
+				{AccountID: alice, Amount: 100, Currency: "USD"},
+			},
+			wantErr: ErrInvalidEntry,
+		},
+		{
+			name: "zero amount",
+			entries: []domain.EntryRequest{
+				{AccountID: alice, Amount: 0, Currency: "USD"},
+				{AccountID: bob, Amount: 0, Currency: "USD"},
+			},
+			wantErr: ErrInvalidEntry,
+		},
+		{
+			name: "unsupported currency",
+			entries: []domain.EntryRequest{
+				{AccountID: alice, Amount: -100, Currency: "EUR"},
+				{AccountID: bob, Amount: 100, Currency: "EUR"},
+			},
+			wantErr: ErrInvalidEntry,
+		},
+		{
+			name: "amount above the entry cap",
+			entries: []domain.EntryRequest{
+				{AccountID: alice, Amount: -(maxEntryAmount + 1), Currency: "USD"},
+				{AccountID: bob, Amount: maxEntryAmount + 1, Currency: "USD"},
+			},
+			wantErr: ErrInvalidEntry,
+		},
+		{
+			name: "sum wraps around to zero",
+			entries: []domain.EntryRequest{
+				{AccountID: alice, Amount: math.MaxInt64, Currency: "USD"},
+				{AccountID: bob, Amount: math.MaxInt64, Currency: "USD"},
+				{AccountID: SystemPoolUSD, Amount: 2, Currency: "USD"},
+			},
+			wantErr: ErrInvalidEntry,
+		},
+		{
+			name: "metadata is not an object",
+			entries: []domain.EntryRequest{
+				{AccountID: alice, Amount: -100, Currency: "USD", Metadata: []byte(`[1,2]`)},
+				{AccountID: bob, Amount: 100, Currency: "USD"},
+			},
+			wantErr: ErrInvalidEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePosting(&domain.Posting{Entries: tt.entries})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidatePosting_EffectiveAt(t *testing.T) {
+	entries := []domain.EntryRequest{
+		{AccountID: alice, Amount: -100, Currency: "USD"},
+		{AccountID: bob, Amount: 100, Currency: "USD"},
+	}
+
+	backdated := &domain.Posting{Entries: entries, EffectiveAt: time.Now().Add(-24 * time.Hour)}
+	if err := validatePosting(backdated); err != nil {
+		t.Errorf("Expected a backdated posting to be valid, got %v", err)
+	}
+
+	future := &domain.Posting{Entries: entries, EffectiveAt: time.Now().Add(time.Hour)}
+	if err := validatePosting(future); !errors.Is(err, ErrInvalidTransaction) {
+		t.Errorf("Expected ErrInvalidTransaction for a future effective date, got %v", err)
+	}
+}
+
+func TestValidatePosting_Metadata(t *testing.T) {
+	entries := []domain.EntryRequest{
+		{AccountID: alice, Amount: -100, Currency: "USD"},
+		{AccountID: bob, Amount: 100, Currency: "USD"},
+	}
+
+	tagged := &domain.Posting{Entries: entries, Metadata: []byte(`{"order_id":"42"}`)}
+	if err := validatePosting(tagged); err != nil {
+		t.Errorf("Expected object metadata to be valid, got %v", err)
+	}
+
+	invalid := &domain.Posting{Entries: entries, Metadata: []byte(`"order 42"`)}
+	if err := validatePosting(invalid); !errors.Is(err, ErrInvalidTransaction) {
+		t.Errorf("Expected ErrInvalidTransaction for non-object metadata, got %v", err)
+	}
+}
+
+func TestValidateAccounts(t *testing.T) {
+	accounts := []repo.Account{
+		{ID: alice, Currency: repo.CurrencyUSD},
+		{ID: bob, Currency: repo.CurrencyBRL},
+	}
+
+	posting := &domain.Posting{Entries: []domain.EntryRequest{
+		{AccountID: alice, Amount: -100, Currency: "USD"},
+		{AccountID: bob, Amount: 100, Currency: "USD"},
+	}}
+	if err := validateAccounts(posting, accounts); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+
+	posting.Entries[1].AccountID = uuid.New()
+	if err := validateAccounts(posting, accounts); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+
+	closed := []repo.Account{
+		{ID: alice, Currency: repo.CurrencyUSD, Status: domain.AccountStatusClosed},
+		{ID: bob, Currency: repo.CurrencyUSD, Status: domain.AccountStatusOpen},
+	}
+	posting.Entries[1].AccountID = bob
+	if err := validateAccounts(posting, closed); !errors.Is(err, ErrAccountClosed) {
+		t.Errorf("Expected ErrAccountClosed, got %v", err)
+	}
+}
+
+func TestNetDebits(t *testing.T) {
+	posting := &domain.Posting{Entries: []domain.EntryRequest{
+		{AccountID: alice, Amount: -100, Currency: "USD"},
+		{AccountID: alice, Amount: 30, Currency: "USD"},
+		{AccountID: bob, Amount: 70, Currency: "USD"},
+	}}
+
+	debits, err := netDebits(posting)
+	if err != nil {
+		t.Fatalf("netDebits returned error: %v", err)
+	}
+	if len(debits) != 1 {
+		t.Fatalf("Expected a single debited account, got %d", len(debits))
+	}
+	if debits[alice] != 70 {
+		t.Errorf("Expected alice to be debited 70, got %d", debits[alice])
+	}
+}
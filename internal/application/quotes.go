@@ -0,0 +1,203 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultQuoteTTL = 30 * time.Second
+
+var (
+	ErrInvalidQuote  error = errors.New("invalid fx quote")
+	ErrQuoteNotFound error = errors.New("fx quote not found")
+	ErrQuoteExpired  error = errors.New("fx quote has expired")
+	ErrQuoteUsed     error = errors.New("fx quote was already used")
+)
+
+// CreateQuote prices a conversion and locks the price until the quote
+// expires. The rates are stored as they were rounded when priced, so the rate
+// shown is exactly the one a transfer booking the quote applies.
+func (l *LedgerService) CreateQuote(ctx context.Context, request *domain.NewQuote) (*domain.Quote, error) {
+	source, target := Currency(request.SourceCurrency), Currency(request.TargetCurrency)
+	if err := validateQuote(source, target, request.Amount); err != nil {
+		return nil, err
+	}
+
+	price, err := l.price(ctx, fx.Pair{Base: string(source), Quote: string(target)}, request.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := convertAmount(request.Amount, price.Rate.Value)
+	if err != nil {
+		return nil, err
+	}
+	if converted <= 0 {
+		return nil, fmt.Errorf("%w: %d %s converts to nothing", ErrInvalidQuote, request.Amount, source)
+	}
+
+	ttl := defaultQuoteTTL
+	if l.cfg.FX_QUOTE_TTL_SECONDS > 0 {
+		ttl = time.Duration(l.cfg.FX_QUOTE_TTL_SECONDS) * time.Second
+	}
+
+	midRate, err := numericRate(price.Mid.Value)
+	if err != nil {
+		return nil, err
+	}
+	appliedRate, err := numericRate(price.Rate.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := l.store.CreateFxQuote(ctx, repo.CreateFxQuoteParams{
+		ID:             uuid.New(),
+		SourceCurrency: repo.Currency(source),
+		TargetCurrency: repo.Currency(target),
+		SourceAmount:   request.Amount,
+		TargetAmount:   converted,
+		MidRate:        midRate,
+		SpreadBps:      price.SpreadBps,
+		Rate:           appliedRate,
+		RateSource:     price.Rate.Source,
+		ExpiresAt:      pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating fx quote: %w", err)
+	}
+
+	return newQuote(created), nil
+}
+
+// GetQuote returns a quote by its id.
+func (l *LedgerService) GetQuote(ctx context.Context, id uuid.UUID) (*domain.Quote, error) {
+	quote, err := l.store.GetFxQuote(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrQuoteNotFound, id)
+		}
+		return nil, fmt.Errorf("error fetching fx quote %s: %w", id, err)
+	}
+
+	return newQuote(quote), nil
+}
+
+// quotedPrice checks that the quote of a transfer covers it and returns the
+// locked price. The quote is only used up by the posting that books it.
+func (l *LedgerService) quotedPrice(ctx context.Context, transaction *domain.Transaction, source, target Currency) (fxPrice, error) {
+	quote, err := l.GetQuote(ctx, transaction.QuoteID)
+	if err != nil {
+		return fxPrice{}, err
+	}
+
+	if err := checkQuote(quote, source, target, transaction.Value, time.Now()); err != nil {
+		return fxPrice{}, err
+	}
+
+	return fxPrice{Mid: quote.MidRate, Rate: quote.Rate, SpreadBps: quote.SpreadBps}, nil
+}
+
+// useQuote marks a quote as booked by a transaction. The update only matches
+// an unused quote that has not expired, so a quote books a single transfer
+// even when two of them race for it.
+func useQuote(ctx context.Context, qtx *repo.Queries, id, transactionID uuid.UUID) error {
+	_, err := qtx.UseFxQuote(ctx, repo.UseFxQuoteParams{
+		ID:            id,
+		TransactionID: uuid.NullUUID{UUID: transactionID, Valid: true},
+	})
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("error using fx quote %s: %w", id, err)
+	}
+
+	quote, err := qtx.GetFxQuote(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", ErrQuoteNotFound, id)
+		}
+		return fmt.Errorf("error fetching fx quote %s: %w", id, err)
+	}
+	if quote.UsedAt.Valid {
+		return fmt.Errorf("%w: %s", ErrQuoteUsed, id)
+	}
+	return fmt.Errorf("%w: %s", ErrQuoteExpired, id)
+}
+
+func validateQuote(source, target Currency, amount int64) error {
+	if amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidQuote)
+	}
+	if _, ok := systemPools[source]; !ok {
+		return fmt.Errorf("%w: unsupported currency %q", ErrInvalidQuote, source)
+	}
+	if _, ok := systemPools[target]; !ok {
+		return fmt.Errorf("%w: unsupported currency %q", ErrInvalidQuote, target)
+	}
+	if source == target {
+		return fmt.Errorf("%w: source and target currency must differ", ErrInvalidQuote)
+	}
+	return nil
+}
+
+// checkQuote makes sure a quote prices exactly the given conversion and can
+// still be booked at now.
+func checkQuote(quote *domain.Quote, source, target Currency, amount int64, now time.Time) error {
+	if quote.SourceCurrency != string(source) || quote.TargetCurrency != string(target) {
+		return fmt.Errorf("%w: quote %s converts %s to %s, transfer converts %s to %s",
+			ErrInvalidQuote, quote.ID, quote.SourceCurrency, quote.TargetCurrency, source, target)
+	}
+	if quote.SourceAmount != amount {
+		return fmt.Errorf("%w: quote %s is for %d, transfer is for %d", ErrInvalidQuote, quote.ID, quote.SourceAmount, amount)
+	}
+	if quote.TransactionID != uuid.Nil {
+		return fmt.Errorf("%w: %s", ErrQuoteUsed, quote.ID)
+	}
+	if !quote.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: %s", ErrQuoteExpired, quote.ID)
+	}
+	return nil
+}
+
+func newQuote(quote repo.FxQuote) *domain.Quote {
+	pair := fx.Pair{Base: string(quote.SourceCurrency), Quote: string(quote.TargetCurrency)}
+
+	result := &domain.Quote{
+		ID:             quote.ID,
+		SourceCurrency: string(quote.SourceCurrency),
+		TargetCurrency: string(quote.TargetCurrency),
+		SourceAmount:   quote.SourceAmount,
+		TargetAmount:   quote.TargetAmount,
+		MidRate: fx.Rate{
+			Pair:   pair,
+			Value:  ratFromNumeric(quote.MidRate),
+			Source: quote.RateSource,
+			AsOf:   quote.CreatedAt.Time,
+		},
+		SpreadBps: quote.SpreadBps,
+		Rate: fx.Rate{
+			Pair:   pair,
+			Value:  ratFromNumeric(quote.Rate),
+			Source: quote.RateSource,
+			AsOf:   quote.CreatedAt.Time,
+		},
+		ExpiresAt: quote.ExpiresAt.Time,
+		UsedAt:    quote.UsedAt.Time,
+		CreatedAt: quote.CreatedAt.Time,
+	}
+	if quote.TransactionID.Valid {
+		result.TransactionID = quote.TransactionID.UUID
+	}
+
+	return result
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/google/uuid"
+)
+
+func TestCheckQuote(t *testing.T) {
+	now := time.Now()
+	open := domain.Quote{
+		ID:             uuid.New(),
+		SourceCurrency: "USD",
+		TargetCurrency: "BRL",
+		SourceAmount:   10000,
+		ExpiresAt:      now.Add(time.Minute),
+	}
+
+	used := open
+	used.TransactionID = uuid.New()
+
+	expired := open
+	expired.ExpiresAt = now
+
+	tests := []struct {
+		name   string
+		quote  domain.Quote
+		source Currency
+		target Currency
+		amount int64
+		want   error
+	}{
+		{name: "open quote", quote: open, source: CurrencyUSD, target: CurrencyBRL, amount: 10000},
+		{name: "other pair", quote: open, source: CurrencyBRL, target: CurrencyUSD, amount: 10000, want: ErrInvalidQuote},
+		{name: "other amount", quote: open, source: CurrencyUSD, target: CurrencyBRL, amount: 9999, want: ErrInvalidQuote},
+		{name: "used quote", quote: used, source: CurrencyUSD, target: CurrencyBRL, amount: 10000, want: ErrQuoteUsed},
+		{name: "expired quote", quote: expired, source: CurrencyUSD, target: CurrencyBRL, amount: 10000, want: ErrQuoteExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkQuote(&tt.quote, tt.source, tt.target, tt.amount, now)
+			if tt.want == nil && err != nil {
+				t.Errorf("Expected the quote to book the transfer, got %v", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateQuote(t *testing.T) {
+	if err := validateQuote(CurrencyUSD, CurrencyBRL, 100); err != nil {
+		t.Errorf("Expected a USD to BRL quote to be valid, got %v", err)
+	}
+	if err := validateQuote(CurrencyUSD, CurrencyUSD, 100); !errors.Is(err, ErrInvalidQuote) {
+		t.Errorf("Expected ErrInvalidQuote for a single currency, got %v", err)
+	}
+	if err := validateQuote(CurrencyUSD, "EUR", 100); !errors.Is(err, ErrInvalidQuote) {
+		t.Errorf("Expected ErrInvalidQuote for an unsupported currency, got %v", err)
+	}
+	if err := validateQuote(CurrencyUSD, CurrencyBRL, 0); !errors.Is(err, ErrInvalidQuote) {
+		t.Errorf("Expected ErrInvalidQuote for a zero amount, got %v", err)
+	}
+}
+
+func TestApplySpread(t *testing.T) {
+	mid := fx.Rate{Pair: fx.Pair{Base: "USD", Quote: "BRL"}, Value: big.NewRat(533, 100), Source: "static"}
+
+	tests := []struct {
+		spread int32
+		want   string
+	}{
+		{spread: 0, want: "5.33"},
+		{spread: 50, want: "5.30335"},
+		{spread: 100, want: "5.2767"},
+	}
+
+	for _, tt := range tests {
+		rate, err := applySpread(mid, tt.spread)
+		if err != nil {
+			t.Fatalf("applySpread(%d) returned error: %v", tt.spread, err)
+		}
+		if got := rate.Decimal(); got != tt.want || rate.Source != "static" {
+			t.Errorf("applySpread(%d) = %s from %s, want %s from static", tt.spread, got, rate.Source, tt.want)
+		}
+	}
+
+	// The inverse of 5.33 has no finite decimal form, the quoted rate is the
+	// rounded one so what is shown and what is booked can not drift apart
+	rate, err := applySpread(mid.Inverse(), 0)
+	if err != nil {
+		t.Fatalf("applySpread returned error: %v", err)
+	}
+	if shown, _ := fx.ParseRate(rate.Decimal()); shown.Cmp(rate.Value) != 0 {
+		t.Errorf("Expected the quoted rate to equal its decimal form %s, got %s", rate.Decimal(), rate.Value.RatString())
+	}
+}
@@ -0,0 +1,144 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrRateNotFound error = errors.New("no rate recorded for the currency pair")
+
+// RateRecorder keeps every rate the providers quote in Postgres, where the
+// historical lookups and the revaluation report find them.
+type RateRecorder struct {
+	store *repo.SQLStore
+}
+
+func NewRateRecorder(store *repo.SQLStore) *RateRecorder {
+	return &RateRecorder{store: store}
+}
+
+func (r *RateRecorder) RecordRate(ctx context.Context, rate fx.Rate) error {
+	value, err := numericRate(rate.Value)
+	if err != nil {
+		return err
+	}
+
+	err = r.store.CreateFxRate(ctx, repo.CreateFxRateParams{
+		ID:     uuid.New(),
+		Base:   repo.Currency(rate.Pair.Base),
+		Quote:  repo.Currency(rate.Pair.Quote),
+		Rate:   value,
+		Source: rate.Source,
+		AsOf:   pgtype.Timestamptz{Time: rate.AsOf, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("error recording rate %s: %w", rate.Pair, err)
+	}
+
+	return nil
+}
+
+// GetRate returns the rate of a pair at a point in time, now when at is
+// zero: the latest one recorded at or before it, in either direction.
+func (l *LedgerService) GetRate(ctx context.Context, pair fx.Pair, at time.Time) (fx.Rate, error) {
+	if err := validateRatePair(pair); err != nil {
+		return fx.Rate{}, err
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	row, err := l.store.GetFxRateAt(ctx, repo.GetFxRateAtParams{
+		Base:  repo.Currency(pair.Base),
+		Quote: repo.Currency(pair.Quote),
+		At:    pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fx.Rate{}, fmt.Errorf("%w: %s at %s", ErrRateNotFound, pair, at.Format(time.RFC3339))
+		}
+		return fx.Rate{}, fmt.Errorf("error fetching rate %s at %s: %w", pair, at, err)
+	}
+
+	return recordedRate(pair, row), nil
+}
+
+// rateHistory returns every rate recorded for a pair up to at, oldest first
+func (l *LedgerService) rateHistory(ctx context.Context, pair fx.Pair, at time.Time) ([]fx.Rate, error) {
+	rows, err := l.store.GetFxRateHistory(ctx, repo.GetFxRateHistoryParams{
+		Base:  repo.Currency(pair.Base),
+		Quote: repo.Currency(pair.Quote),
+		At:    pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching the history of rate %s: %w", pair, err)
+	}
+
+	history := make([]fx.Rate, len(rows))
+	for i, row := range rows {
+		history[i] = recordedRate(pair, row)
+	}
+	return history, nil
+}
+
+func validateRatePair(pair fx.Pair) error {
+	if _, ok := systemPools[Currency(pair.Base)]; !ok {
+		return fmt.Errorf("%w: unsupported currency %q", fx.ErrInvalidPair, pair.Base)
+	}
+	if _, ok := systemPools[Currency(pair.Quote)]; !ok {
+		return fmt.Errorf("%w: unsupported currency %q", fx.ErrInvalidPair, pair.Quote)
+	}
+	if pair.Base == pair.Quote {
+		return fmt.Errorf("%w: %s", fx.ErrInvalidPair, pair)
+	}
+	return nil
+}
+
+// recordedRate reads a recorded rate as the price of pair, inverting it when
+// it was recorded the other way around.
+func recordedRate(pair fx.Pair, row repo.FxRate) fx.Rate {
+	rate := fx.Rate{
+		Pair:   fx.Pair{Base: string(row.Base), Quote: string(row.Quote)},
+		Value:  ratFromNumeric(row.Rate),
+		Source: row.Source,
+		AsOf:   row.AsOf.Time,
+	}
+	if rate.Pair != pair {
+		return rate.Inverse()
+	}
+	return rate
+}
+
+func numericRate(rate *big.Rat) (pgtype.Numeric, error) {
+	var numeric pgtype.Numeric
+	if err := numeric.Scan(fx.Rate{Value: rate}.Decimal()); err != nil {
+		return pgtype.Numeric{}, fmt.Errorf("error encoding rate %s: %w", rate.RatString(), err)
+	}
+	return numeric, nil
+}
+
+func ratFromNumeric(numeric pgtype.Numeric) *big.Rat {
+	if !numeric.Valid || numeric.Int == nil {
+		return nil
+	}
+
+	rate := new(big.Rat).SetInt(numeric.Int)
+	exp := int64(numeric.Exp)
+	if exp < 0 {
+		exp = -exp
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+	if numeric.Exp < 0 {
+		return rate.Quo(rate, scale)
+	}
+	return rate.Mul(rate, scale)
+}
@@ -0,0 +1,21 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+)
+
+func TestNumericRate(t *testing.T) {
+	for _, s := range []string{"5.33", "0.187617260787992495", "1", "1200.5"} {
+		rate, _ := fx.ParseRate(s)
+
+		numeric, err := numericRate(rate)
+		if err != nil {
+			t.Fatalf("numericRate(%s) returned error: %v", s, err)
+		}
+		if got := ratFromNumeric(numeric); got.Cmp(rate) != 0 {
+			t.Errorf("Expected %s to survive the numeric round trip, got %s", s, got.RatString())
+		}
+	}
+}
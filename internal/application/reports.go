@@ -0,0 +1,214 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInvalidReport error = errors.New("invalid report period")
+
+// GetTrialBalance sums the debits and credits of every account up to asOf,
+// now when zero. Entries are picked by their effective date. An unbalanced
+// currency means the ledger lost its core invariant and is logged as an error
+// on top of being flagged in the report.
+func (l *LedgerService) GetTrialBalance(ctx context.Context, asOf time.Time) (domain.TrialBalance, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+
+	totals, err := l.accountTotals(ctx, time.Time{}, asOf)
+	if err != nil {
+		return domain.TrialBalance{}, err
+	}
+
+	report := buildTrialBalance(asOf, totals)
+	for _, currency := range report.Currencies {
+		if !currency.Balanced {
+			slog.Error("trial balance does not balance",
+				slog.String("currency", currency.Currency),
+				slog.Int64("debits", currency.Debits),
+				slog.Int64("credits", currency.Credits),
+				slog.Time("as_of", asOf),
+			)
+		}
+	}
+
+	return report, nil
+}
+
+// GetBalanceSheet groups the asset, liability and equity balances at asOf,
+// now when zero.
+func (l *LedgerService) GetBalanceSheet(ctx context.Context, asOf time.Time) (domain.BalanceSheet, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+
+	totals, err := l.accountTotals(ctx, time.Time{}, asOf)
+	if err != nil {
+		return domain.BalanceSheet{}, err
+	}
+
+	return buildBalanceSheet(asOf, totals), nil
+}
+
+// GetIncomeStatement groups the revenue and expense activity effective after
+// from and up to to. A zero from starts at the first entry and a zero to
+// means now.
+func (l *LedgerService) GetIncomeStatement(ctx context.Context, from, to time.Time) (domain.IncomeStatement, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if !from.IsZero() && from.After(to) {
+		return domain.IncomeStatement{}, fmt.Errorf("%w: from %s is after to %s", ErrInvalidReport, from, to)
+	}
+
+	totals, err := l.accountTotals(ctx, from, to)
+	if err != nil {
+		return domain.IncomeStatement{}, err
+	}
+
+	return buildIncomeStatement(from, to, totals), nil
+}
+
+// accountTotals returns the accounts that had entries effective in the
+// period, ordered by currency and class.
+func (l *LedgerService) accountTotals(ctx context.Context, from, to time.Time) ([]domain.AccountTotals, error) {
+	rows, err := l.store.GetAccountTotals(ctx, repo.GetAccountTotalsParams{
+		FromDate: pgtype.Timestamptz{Time: from, Valid: !from.IsZero()},
+		ToDate:   pgtype.Timestamptz{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error summing account totals up to %s: %w", to, err)
+	}
+
+	return newAccountTotals(rows), nil
+}
+
+// newAccountTotals turns summed entries into account totals. Positive
+// amounts are credits and negative ones debits, and the balance is taken on
+// the normal side of the account, so it is positive whenever the account
+// holds what its class expects.
+func newAccountTotals(rows []repo.GetAccountTotalsRow) []domain.AccountTotals {
+	totals := make([]domain.AccountTotals, 0, len(rows))
+	for _, row := range rows {
+		if row.Debits == 0 && row.Credits == 0 {
+			continue
+		}
+
+		balance := row.Credits - row.Debits
+		if row.NormalBalance == domain.NormalBalanceDebit {
+			balance = -balance
+		}
+
+		totals = append(totals, domain.AccountTotals{
+			AccountID:     row.ID,
+			Name:          row.Name,
+			Currency:      string(row.Currency),
+			Class:         string(row.AccountClass),
+			NormalBalance: row.NormalBalance,
+			Debits:        row.Debits,
+			Credits:       row.Credits,
+			Balance:       balance,
+		})
+	}
+
+	return totals
+}
+
+// byCurrency splits totals ordered by currency into one group per currency
+func byCurrency(totals []domain.AccountTotals) [][]domain.AccountTotals {
+	var groups [][]domain.AccountTotals
+	for i, total := range totals {
+		if i == 0 || total.Currency != totals[i-1].Currency {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], total)
+	}
+	return groups
+}
+
+func buildTrialBalance(asOf time.Time, totals []domain.AccountTotals) domain.TrialBalance {
+	report := domain.TrialBalance{AsOf: asOf, Balanced: true}
+	for _, group := range byCurrency(totals) {
+		currency := domain.TrialBalanceCurrency{Currency: group[0].Currency, Accounts: group}
+		for _, total := range group {
+			currency.Debits += total.Debits
+			currency.Credits += total.Credits
+		}
+		currency.Balanced = currency.Debits == currency.Credits
+
+		report.Currencies = append(report.Currencies, currency)
+		report.Balanced = report.Balanced && currency.Balanced
+	}
+	return report
+}
+
+func buildBalanceSheet(asOf time.Time, totals []domain.AccountTotals) domain.BalanceSheet {
+	report := domain.BalanceSheet{AsOf: asOf, Balanced: true}
+	for _, group := range byCurrency(totals) {
+		currency := domain.BalanceSheetCurrency{
+			Currency:    group[0].Currency,
+			Assets:      domain.ReportSection{Class: string(repo.AccountClassAsset)},
+			Liabilities: domain.ReportSection{Class: string(repo.AccountClassLiability)},
+			Equity:      domain.ReportSection{Class: string(repo.AccountClassEquity)},
+		}
+		for _, total := range group {
+			switch repo.AccountClass(total.Class) {
+			case repo.AccountClassAsset:
+				addToSection(&currency.Assets, total)
+			case repo.AccountClassLiability:
+				addToSection(&currency.Liabilities, total)
+			case repo.AccountClassEquity:
+				addToSection(&currency.Equity, total)
+			case repo.AccountClassRevenue:
+				currency.NetIncome += total.Balance
+			case repo.AccountClassExpense:
+				currency.NetIncome -= total.Balance
+			}
+		}
+		currency.Balanced = currency.Assets.Total ==
+			currency.Liabilities.Total+currency.Equity.Total+currency.NetIncome
+
+		report.Currencies = append(report.Currencies, currency)
+		report.Balanced = report.Balanced && currency.Balanced
+	}
+	return report
+}
+
+func buildIncomeStatement(from, to time.Time, totals []domain.AccountTotals) domain.IncomeStatement {
+	report := domain.IncomeStatement{From: from, To: to}
+	for _, group := range byCurrency(totals) {
+		currency := domain.IncomeStatementCurrency{
+			Currency: group[0].Currency,
+			Revenue:  domain.ReportSection{Class: string(repo.AccountClassRevenue)},
+			Expenses: domain.ReportSection{Class: string(repo.AccountClassExpense)},
+		}
+		for _, total := range group {
+			switch repo.AccountClass(total.Class) {
+			case repo.AccountClassRevenue:
+				addToSection(&currency.Revenue, total)
+			case repo.AccountClassExpense:
+				addToSection(&currency.Expenses, total)
+			}
+		}
+		if len(currency.Revenue.Accounts) == 0 && len(currency.Expenses.Accounts) == 0 {
+			continue
+		}
+		currency.NetIncome = currency.Revenue.Total - currency.Expenses.Total
+
+		report.Currencies = append(report.Currencies, currency)
+	}
+	return report
+}
+
+func addToSection(section *domain.ReportSection, total domain.AccountTotals) {
+	section.Accounts = append(section.Accounts, total)
+	section.Total += total.Balance
+}
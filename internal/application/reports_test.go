@@ -0,0 +1,139 @@
+package application
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+)
+
+// reportTotals is a USD deposit of 1000 to a wallet, a 10 fee charged on it,
+// 1000 of capital put in a pool and a BRL deposit, as the ledger would have
+// summed them.
+func reportTotals() []domain.AccountTotals {
+	return []domain.AccountTotals{
+		{AccountID: uuid.New(), Currency: "BRL", Class: "asset", NormalBalance: "debit", Debits: 500, Balance: 500},
+		{AccountID: uuid.New(), Currency: "BRL", Class: "liability", NormalBalance: "credit", Credits: 500, Balance: 500},
+		{AccountID: uuid.New(), Currency: "USD", Class: "asset", NormalBalance: "debit", Debits: 2000, Balance: 2000},
+		{AccountID: SystemPoolUSD, Currency: "USD", Class: "equity", NormalBalance: "credit", Credits: 1000, Balance: 1000},
+		{AccountID: alice, Currency: "USD", Class: "liability", NormalBalance: "credit", Debits: 10, Credits: 1000, Balance: 990},
+		{AccountID: uuid.New(), Currency: "USD", Class: "revenue", NormalBalance: "credit", Credits: 10, Balance: 10},
+	}
+}
+
+// seedEntries are the entries the seed migrations post: the pools funded from
+// the World Bank and the first deposits of the two users.
+func seedEntries() []repo.GetEntriesByTransactionIDRow {
+	worldUSD := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	worldBRL := uuid.MustParse("00000000-0000-0000-0000-000000000009")
+	return []repo.GetEntriesByTransactionIDRow{
+		entryRow(worldUSD, -100000000, repo.CurrencyUSD),
+		entryRow(SystemPoolUSD, 100000000, repo.CurrencyUSD),
+		entryRow(worldBRL, -100000000, repo.CurrencyBRL),
+		entryRow(SystemPoolBRL, 100000000, repo.CurrencyBRL),
+		entryRow(worldUSD, -100000, repo.CurrencyUSD),
+		entryRow(alice, 100000, repo.CurrencyUSD),
+		entryRow(worldBRL, -500000, repo.CurrencyBRL),
+		entryRow(bob, 500000, repo.CurrencyBRL),
+	}
+}
+
+func TestBuildBalanceSheet_Seed(t *testing.T) {
+	// Classes as the chart of accounts migration assigns them
+	classes := map[uuid.UUID]repo.AccountClass{
+		uuid.MustParse("00000000-0000-0000-0000-000000000000"): repo.AccountClassAsset,
+		uuid.MustParse("00000000-0000-0000-0000-000000000009"): repo.AccountClassAsset,
+		SystemPoolUSD: repo.AccountClassEquity,
+		SystemPoolBRL: repo.AccountClassEquity,
+		alice:         repo.AccountClassLiability,
+		bob:           repo.AccountClassLiability,
+	}
+
+	// Summed the way GetAccountTotals does
+	var rows []repo.GetAccountTotalsRow
+	index := make(map[uuid.UUID]int)
+	for _, entry := range seedEntries() {
+		i, ok := index[entry.AccountID]
+		if !ok {
+			side, err := normalBalance(classes[entry.AccountID])
+			if err != nil {
+				t.Fatalf("normalBalance returned error: %v", err)
+			}
+			i = len(rows)
+			index[entry.AccountID] = i
+			rows = append(rows, repo.GetAccountTotalsRow{
+				ID:            entry.AccountID,
+				Currency:      entry.Currency,
+				AccountClass:  classes[entry.AccountID],
+				NormalBalance: side,
+			})
+		}
+		if entry.Amount < 0 {
+			rows[i].Debits -= entry.Amount
+		} else {
+			rows[i].Credits += entry.Amount
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Currency < rows[j].Currency })
+
+	totals := newAccountTotals(rows)
+	for _, total := range totals {
+		if total.Balance <= 0 {
+			t.Errorf("Expected %s account %s to hold a positive balance on its normal side, got %d", total.Class, total.AccountID, total.Balance)
+		}
+	}
+
+	report := buildBalanceSheet(time.Now(), totals)
+	if !report.Balanced || len(report.Currencies) != 2 {
+		t.Fatalf("Expected 2 balanced currencies, got %+v", report)
+	}
+	usd := report.Currencies[1]
+	if usd.Assets.Total != 100100000 || usd.Liabilities.Total != 100000 || usd.Equity.Total != 100000000 {
+		t.Errorf("Expected USD assets 100100000, liabilities 100000 and equity 100000000, got %d, %d and %d",
+			usd.Assets.Total, usd.Liabilities.Total, usd.Equity.Total)
+	}
+}
+
+func TestBuildTrialBalance(t *testing.T) {
+	report := buildTrialBalance(time.Now(), reportTotals())
+	if !report.Balanced || len(report.Currencies) != 2 {
+		t.Fatalf("Expected 2 balanced currencies, got %d balanced=%v", len(report.Currencies), report.Balanced)
+	}
+
+	usd := report.Currencies[1]
+	if usd.Currency != "USD" || usd.Debits != 2010 || usd.Credits != 2010 || len(usd.Accounts) != 4 {
+		t.Errorf("Expected USD to total 2010 on both sides over 4 accounts, got %+v", usd)
+	}
+
+	unbalanced := reportTotals()
+	unbalanced[1].Credits = 499
+	if report := buildTrialBalance(time.Now(), unbalanced); report.Balanced || report.Currencies[0].Balanced {
+		t.Error("Expected a missing BRL credit to unbalance the trial balance")
+	}
+}
+
+func TestBuildBalanceSheet(t *testing.T) {
+	report := buildBalanceSheet(time.Now(), reportTotals())
+	if !report.Balanced {
+		t.Fatalf("Expected the balance sheet to balance, got %+v", report)
+	}
+
+	usd := report.Currencies[1]
+	if usd.Assets.Total != 2000 || usd.Liabilities.Total != 990 || usd.Equity.Total != 1000 || usd.NetIncome != 10 {
+		t.Errorf("Expected assets 2000, liabilities 990, equity 1000 and net income 10, got %d, %d, %d and %d",
+			usd.Assets.Total, usd.Liabilities.Total, usd.Equity.Total, usd.NetIncome)
+	}
+}
+
+func TestBuildIncomeStatement(t *testing.T) {
+	report := buildIncomeStatement(time.Time{}, time.Now(), reportTotals())
+	if len(report.Currencies) != 1 {
+		t.Fatalf("Expected only USD to have income, got %d currencies", len(report.Currencies))
+	}
+	if usd := report.Currencies[0]; usd.Revenue.Total != 10 || usd.NetIncome != 10 {
+		t.Errorf("Expected 10 of revenue and net income, got %d and %d", usd.Revenue.Total, usd.NetIncome)
+	}
+}
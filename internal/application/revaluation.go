@@ -0,0 +1,154 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetRevaluation values the foreign currency assets and liabilities in the
+// reporting currency at asOf. The rates are the recorded ones: the closing
+// rate is the latest at or before asOf and every day of movements is carried
+// at the latest rate by the end of that day, or the oldest recorded one when
+// the movements predate the history.
+func (l *LedgerService) GetRevaluation(ctx context.Context, currency string, asOf time.Time) (*domain.Revaluation, error) {
+	if _, ok := systemPools[Currency(currency)]; !ok {
+		return nil, fmt.Errorf("%w: unsupported reporting currency %q", ErrInvalidReport, currency)
+	}
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+
+	flows, err := l.store.GetForeignDailyFlows(ctx, repo.GetForeignDailyFlowsParams{
+		ReportingCurrency: repo.Currency(currency),
+		AsOf:              pgtype.Timestamptz{Time: asOf, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error summing foreign movements up to %s: %w", asOf, err)
+	}
+
+	histories := make(map[string][]fx.Rate)
+	for _, flow := range flows {
+		foreign := string(flow.Currency)
+		if _, ok := histories[foreign]; ok {
+			continue
+		}
+
+		pair := fx.Pair{Base: foreign, Quote: currency}
+		history, err := l.rateHistory(ctx, pair, asOf)
+		if err != nil {
+			return nil, err
+		}
+		if len(history) == 0 {
+			return nil, fmt.Errorf("%w: %s at %s", ErrRateNotFound, pair, asOf.Format(time.RFC3339))
+		}
+		histories[foreign] = history
+	}
+
+	return buildRevaluation(asOf, currency, flows, histories)
+}
+
+// buildRevaluation values the daily movements of every foreign account,
+// ordered by currency and account, with the rate histories of their
+// currencies against the reporting one.
+func buildRevaluation(asOf time.Time, currency string, flows []repo.GetForeignDailyFlowsRow, histories map[string][]fx.Rate) (*domain.Revaluation, error) {
+	report := &domain.Revaluation{AsOf: asOf, Currency: currency}
+
+	for start := 0; start < len(flows); {
+		end := start
+		for end < len(flows) && flows[end].AccountID == flows[start].AccountID {
+			end++
+		}
+		account := flows[start:end]
+		history := histories[string(account[0].Currency)]
+		closing := history[len(history)-1]
+
+		if start == 0 || account[0].Currency != flows[start-1].Currency {
+			report.Rates = append(report.Rates, closing)
+		}
+
+		var balance int64
+		carrying := new(big.Rat)
+		for _, flow := range account {
+			amount := flow.Amount
+			if flow.NormalBalance == domain.NormalBalanceDebit {
+				amount = -amount
+			}
+			balance += amount
+
+			dayEnd := flow.Day.Time.Add(24 * time.Hour)
+			if dayEnd.After(asOf) {
+				dayEnd = asOf
+			}
+			rate := rateAt(history, dayEnd)
+			carrying.Add(carrying, new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate.Value))
+		}
+		start = end
+
+		revalued := new(big.Rat).Mul(new(big.Rat).SetInt64(balance), closing.Value)
+		line := domain.RevaluationLine{
+			AccountID:     account[0].AccountID,
+			Name:          account[0].Name,
+			Currency:      string(account[0].Currency),
+			Class:         string(account[0].AccountClass),
+			NormalBalance: account[0].NormalBalance,
+			Balance:       balance,
+		}
+
+		var err error
+		if line.CarryingValue, err = roundValue(carrying); err != nil {
+			return nil, err
+		}
+		if line.RevaluedValue, err = roundValue(revalued); err != nil {
+			return nil, err
+		}
+		if line.Balance == 0 && line.CarryingValue == 0 {
+			continue
+		}
+
+		// A pricier foreign currency is a gain on what the books own and a
+		// loss on what they owe
+		line.UnrealizedGain = line.RevaluedValue - line.CarryingValue
+		if line.NormalBalance == domain.NormalBalanceCredit {
+			line.UnrealizedGain = -line.UnrealizedGain
+		}
+
+		report.Accounts = append(report.Accounts, line)
+		report.UnrealizedGain += line.UnrealizedGain
+	}
+
+	return report, nil
+}
+
+// rateAt returns the latest rate of a history at or before at, or the oldest
+// one when the history starts later.
+func rateAt(history []fx.Rate, at time.Time) fx.Rate {
+	rate := history[0]
+	for _, candidate := range history {
+		if candidate.AsOf.After(at) {
+			break
+		}
+		rate = candidate
+	}
+	return rate
+}
+
+// roundValue rounds a value in minor units to the nearest one, halves away
+// from zero.
+func roundValue(value *big.Rat) (int64, error) {
+	quo, rem := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(value.Sign())))
+	}
+	if !quo.IsInt64() {
+		return 0, fmt.Errorf("%w: %s overflows", ErrInvalidReport, value.RatString())
+	}
+
+	return quo.Int64(), nil
+}
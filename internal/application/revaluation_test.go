@@ -0,0 +1,83 @@
+package application
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestBuildRevaluation(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	date := func(d int) pgtype.Date { return pgtype.Date{Time: day(d), Valid: true} }
+
+	pair := fx.Pair{Base: "BRL", Quote: "USD"}
+	history := map[string][]fx.Rate{"BRL": {
+		{Pair: pair, Value: big.NewRat(20, 100), AsOf: day(2).Add(9 * time.Hour)},
+		{Pair: pair, Value: big.NewRat(25, 100), AsOf: day(3).Add(9 * time.Hour)},
+	}}
+
+	// Deposits to the BRL wallet as the ledger posts them: the World Bank is
+	// debited and the wallet credited
+	world := uuid.MustParse("00000000-0000-0000-0000-000000000009")
+	flows := []repo.GetForeignDailyFlowsRow{
+		// 1000 BRL on day 1, before the first recorded rate, so it is carried
+		// at the oldest one, and 500 more on day 2 at 0.20
+		{AccountID: world, Currency: "BRL", AccountClass: "asset", NormalBalance: "debit", Day: date(1), Amount: -1000},
+		{AccountID: world, Currency: "BRL", AccountClass: "asset", NormalBalance: "debit", Day: date(2), Amount: -500},
+		{AccountID: alice, Currency: "BRL", AccountClass: "liability", NormalBalance: "credit", Day: date(1), Amount: 1000},
+		{AccountID: alice, Currency: "BRL", AccountClass: "liability", NormalBalance: "credit", Day: date(2), Amount: 500},
+		// Emptied the same day it was funded, nothing left to revalue
+		{AccountID: bob, Currency: "BRL", AccountClass: "liability", NormalBalance: "credit", Day: date(2), Amount: 0},
+	}
+
+	report, err := buildRevaluation(day(4), "USD", flows, history)
+	if err != nil {
+		t.Fatalf("buildRevaluation returned error: %v", err)
+	}
+
+	if len(report.Rates) != 1 || report.Rates[0].Value.Cmp(big.NewRat(1, 4)) != 0 {
+		t.Fatalf("Expected the 0.25 closing rate of BRL, got %+v", report.Rates)
+	}
+	if len(report.Accounts) != 2 {
+		t.Fatalf("Expected the World Bank and the wallet, got %d accounts", len(report.Accounts))
+	}
+
+	asset, wallet := report.Accounts[0], report.Accounts[1]
+	if asset.Balance != 1500 || asset.CarryingValue != 300 || asset.RevaluedValue != 375 || asset.UnrealizedGain != 75 {
+		t.Errorf("Expected the World Bank to gain 75 USD on 1500 BRL, got %+v", asset)
+	}
+	if wallet.Balance != 1500 || wallet.CarryingValue != 300 || wallet.RevaluedValue != 375 || wallet.UnrealizedGain != -75 {
+		t.Errorf("Expected the wallet to lose 75 USD on 1500 BRL, got %+v", wallet)
+	}
+	if report.UnrealizedGain != 0 {
+		t.Errorf("Expected the BRL held to offset the BRL owed, got %d", report.UnrealizedGain)
+	}
+}
+
+func TestRoundValue(t *testing.T) {
+	tests := []struct {
+		value *big.Rat
+		want  int64
+	}{
+		{value: big.NewRat(5, 2), want: 3},
+		{value: big.NewRat(-5, 2), want: -3},
+		{value: big.NewRat(7, 3), want: 2},
+		{value: big.NewRat(-7, 3), want: -2},
+		{value: big.NewRat(42, 1), want: 42},
+	}
+
+	for _, tt := range tests {
+		got, err := roundValue(tt.value)
+		if err != nil {
+			t.Fatalf("roundValue(%s) returned error: %v", tt.value.RatString(), err)
+		}
+		if got != tt.want {
+			t.Errorf("roundValue(%s) = %d, want %d", tt.value.RatString(), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrTransactionNotFound error = errors.New("transaction not found")
+	ErrAlreadyReversed     error = errors.New("transaction is already fully reversed")
+	ErrNotReversible       error = errors.New("transaction can not be reversed")
+	ErrInvalidReversal     error = errors.New("invalid reversal amount")
+)
+
+// ReverseTransaction posts a new transaction whose entries negate the
+// original one, fully or partially, and updates the status of the original.
+// The original row is locked so two reversals can not race each other.
+func (l *LedgerService) ReverseTransaction(ctx context.Context, reversal *domain.Reversal) (*domain.TransactionResult, error) {
+	if reversal.Amount < 0 {
+		return nil, fmt.Errorf("%w: amount must not be negative", ErrInvalidReversal)
+	}
+
+	externalID := idempotencyKey(reversal.IdempotencyKey)
+	if externalID.Valid {
+		replay, err := l.replayTransaction(ctx, externalID)
+		if err != nil {
+			return nil, err
+		}
+		if replay != nil {
+			return replay, nil
+		}
+	}
+
+	var result *domain.TransactionResult
+	err := l.runInTx(ctx, func(qtx *repo.Queries) error {
+		var err error
+		result, err = l.reverseInTx(ctx, qtx, reversal, externalID)
+		return err
+	})
+	if errors.Is(err, errDuplicateExternalID) {
+		return l.replayTransaction(ctx, externalID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (l *LedgerService) reverseInTx(ctx context.Context, qtx *repo.Queries, reversal *domain.Reversal, externalID pgtype.Text) (*domain.TransactionResult, error) {
+	original, err := qtx.GetTransactionForUpdate(ctx, reversal.TransactionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, reversal.TransactionID)
+		}
+		return nil, fmt.Errorf("error fetching transaction %s: %w", reversal.TransactionID, err)
+	}
+
+	switch {
+	case original.Status == domain.StatusReversed:
+		return nil, ErrAlreadyReversed
+	case original.ReversesTransactionID.Valid:
+		return nil, fmt.Errorf("%w: it is itself a reversal", ErrNotReversible)
+	case original.Status != domain.StatusPosted && original.Status != domain.StatusPartiallyReversed:
+		return nil, fmt.Errorf("%w: status is %s", ErrNotReversible, original.Status)
+	}
+
+	entries, err := qtx.GetEntriesByTransactionID(ctx, original.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching entries for transaction %s: %w", original.ID, err)
+	}
+
+	rows, err := qtx.GetReversedEntryAmounts(ctx, uuid.NullUUID{UUID: original.ID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reversed amounts of %s: %w", original.ID, err)
+	}
+	reversed := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		reversed[row.EntryID] = row.Reversed
+	}
+
+	legs, full, err := reversalLegs(entries, reversal.Amount, reversed)
+	if err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("reversal of %s", original.ID)
+	if reversal.Reason != "" {
+		description += ": " + reversal.Reason
+	}
+
+	posting := &domain.Posting{
+		IdempotencyKey:        reversal.IdempotencyKey,
+		Description:           description,
+		CreatedBy:             reversal.CreatedBy,
+		Entries:               legs,
+		ReversesTransactionID: original.ID,
+	}
+	if err := validatePosting(posting); err != nil {
+		return nil, err
+	}
+
+	result, err := l.postInTx(ctx, qtx, posting, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := domain.StatusPartiallyReversed
+	if full {
+		status = domain.StatusReversed
+	}
+
+	err = qtx.UpdateTransactionStatus(ctx, repo.UpdateTransactionStatusParams{ID: original.ID, Status: status})
+	if err != nil {
+		return nil, fmt.Errorf("error updating status of transaction %s: %w", original.ID, err)
+	}
+
+	return result, nil
+}
+
+// reversalLegs builds the inverted entries of a transaction. amount is the
+// gross amount to reverse (the sum of the credited legs), zero meaning all of
+// what is left, and reversed holds what earlier reversals already posted
+// against each entry, keyed by entry id. Partial reversals scale what is left
+// of every leg proportionally and are only supported for single-currency
+// transactions, the final one negates exactly what is left. It also reports
+// whether the transaction ends up fully reversed.
+func reversalLegs(entries []repo.GetEntriesByTransactionIDRow, amount int64, reversed map[uuid.UUID]int64) ([]domain.EntryRequest, bool, error) {
+	if len(entries) == 0 {
+		return nil, false, fmt.Errorf("%w: transaction has no entries", ErrNotReversible)
+	}
+
+	// Reversals carry the opposite sign, so what is left keeps the sign of
+	// the entry
+	left := make([]int64, len(entries))
+	var remaining int64
+	currencies := make(map[repo.Currency]bool)
+	for i, entry := range entries {
+		currencies[entry.Currency] = true
+		left[i] = entry.Amount + reversed[entry.ID]
+		if entry.Amount > 0 {
+			remaining += left[i]
+		}
+	}
+
+	if remaining <= 0 {
+		return nil, false, ErrAlreadyReversed
+	}
+
+	target := amount
+	if target == 0 {
+		target = remaining
+	}
+	if target > remaining {
+		return nil, false, fmt.Errorf("%w: %d requested but only %d left to reverse", ErrInvalidReversal, target, remaining)
+	}
+
+	// The final reversal negates what is left of every entry, any currency
+	// mix, so the reversals add up to the exact inverse of the original
+	if target == remaining {
+		legs := make([]domain.EntryRequest, 0, len(entries))
+		for i, entry := range entries {
+			if left[i] != 0 {
+				legs = append(legs, reversalLeg(entry, -left[i]))
+			}
+		}
+		return legs, true, nil
+	}
+
+	if len(currencies) > 1 {
+		return nil, false, fmt.Errorf("%w: partial reversals need a single-currency transaction", ErrNotReversible)
+	}
+
+	// Scale each side separately so both sum exactly to the target
+	var credits, debits []int
+	for i, entry := range entries {
+		if entry.Amount > 0 {
+			credits = append(credits, i)
+		} else {
+			debits = append(debits, i)
+		}
+	}
+
+	amounts := make([]int64, len(entries))
+	scaleSide(left, credits, remaining, target, amounts)
+	scaleSide(left, debits, remaining, target, amounts)
+
+	legs := make([]domain.EntryRequest, 0, len(entries))
+	for i, entry := range entries {
+		if amounts[i] == 0 {
+			continue
+		}
+		legs = append(legs, reversalLeg(entry, -amounts[i]))
+	}
+
+	return legs, false, nil
+}
+
+// scaleSide scales what is left of the given entries by target/remaining,
+// keeping their sign. The rounding residual goes to the largest entry of the
+// side first, and never takes an entry past what is left of it.
+func scaleSide(left []int64, side []int, remaining, target int64, amounts []int64) {
+	if len(side) == 0 {
+		return
+	}
+
+	var total int64
+	largest := 0
+	for n, i := range side {
+		magnitude := abs(left[i])
+		scaled := new(big.Int).Mul(big.NewInt(magnitude), big.NewInt(target))
+		scaled.Quo(scaled, big.NewInt(remaining))
+
+		amounts[i] = scaled.Int64()
+		total += amounts[i]
+		if magnitude > abs(left[side[largest]]) {
+			largest = n
+		}
+	}
+
+	residual := target - total
+	for n := range side {
+		i := side[(largest+n)%len(side)]
+		step := min(residual, abs(left[i])-amounts[i])
+		amounts[i] += step
+		residual -= step
+	}
+
+	for _, i := range side {
+		if left[i] < 0 {
+			amounts[i] = -amounts[i]
+		}
+	}
+}
+
+func reversalLeg(entry repo.GetEntriesByTransactionIDRow, amount int64) domain.EntryRequest {
+	metadata, _ := json.Marshal(map[string]string{"reversal_of": entry.ID.String()})
+	return domain.EntryRequest{
+		AccountID: entry.AccountID,
+		Amount:    amount,
+		Currency:  string(entry.Currency),
+		Metadata:  metadata,
+	}
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,145 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+)
+
+func entryRow(account uuid.UUID, amount int64, currency repo.Currency) repo.GetEntriesByTransactionIDRow {
+	return repo.GetEntriesByTransactionIDRow{ID: uuid.New(), AccountID: account, Amount: amount, Currency: currency}
+}
+
+func TestReversalLegs_Full(t *testing.T) {
+	entries := []repo.GetEntriesByTransactionIDRow{
+		entryRow(alice, -10000, repo.CurrencyUSD),
+		entryRow(SystemPoolUSD, 10000, repo.CurrencyUSD),
+		entryRow(SystemPoolBRL, -53300, repo.CurrencyBRL),
+		entryRow(bob, 53300, repo.CurrencyBRL),
+	}
+
+	legs, full, err := reversalLegs(entries, 0, nil)
+	if err != nil {
+		t.Fatalf("reversalLegs returned error: %v", err)
+	}
+	if !full {
+		t.Errorf("Expected a full reversal")
+	}
+	for i, leg := range legs {
+		if leg.Amount != -entries[i].Amount || leg.AccountID != entries[i].AccountID {
+			t.Errorf("Leg %d should negate the original entry, got %+v", i, leg)
+		}
+	}
+}
+
+func TestReversalLegs_Partial(t *testing.T) {
+	entries := []repo.GetEntriesByTransactionIDRow{
+		entryRow(alice, -10100, repo.CurrencyUSD),
+		entryRow(bob, 10000, repo.CurrencyUSD),
+		entryRow(SystemFeeRevenueUSD, 100, repo.CurrencyUSD),
+	}
+
+	legs, full, err := reversalLegs(entries, 3333, nil)
+	if err != nil {
+		t.Fatalf("reversalLegs returned error: %v", err)
+	}
+	if full {
+		t.Errorf("Expected a partial reversal")
+	}
+	if err := validatePosting(&domain.Posting{Entries: legs}); err != nil {
+		t.Fatalf("Expected balanced legs, got %v", err)
+	}
+
+	var credited int64
+	for _, leg := range legs {
+		if leg.Amount > 0 {
+			credited += leg.Amount
+		}
+	}
+	if credited != 3333 {
+		t.Errorf("Expected 3333 to be reversed, got %d", credited)
+	}
+
+	// Reversing the rest closes the transaction
+	_, full, err = reversalLegs(entries, 0, reversedAmounts(entries, legs))
+	if err != nil {
+		t.Fatalf("reversalLegs returned error: %v", err)
+	}
+	if !full {
+		t.Errorf("Expected the remaining reversal to be full")
+	}
+}
+
+func TestReversalLegs_PartialThenRest(t *testing.T) {
+	entries := []repo.GetEntriesByTransactionIDRow{
+		entryRow(alice, -10100, repo.CurrencyUSD),
+		entryRow(bob, 10000, repo.CurrencyUSD),
+		entryRow(SystemFeeRevenueUSD, 100, repo.CurrencyUSD),
+	}
+
+	first, _, err := reversalLegs(entries, 1, nil)
+	if err != nil {
+		t.Fatalf("reversalLegs returned error: %v", err)
+	}
+	rest, full, err := reversalLegs(entries, 0, reversedAmounts(entries, first))
+	if err != nil {
+		t.Fatalf("reversalLegs returned error: %v", err)
+	}
+	if !full {
+		t.Errorf("Expected the remaining reversal to be full")
+	}
+
+	totals := make(map[uuid.UUID]int64)
+	for _, leg := range append(first, rest...) {
+		totals[leg.AccountID] += leg.Amount
+	}
+	for _, entry := range entries {
+		if totals[entry.AccountID] != -entry.Amount {
+			t.Errorf("Expected account %s to be reversed by %d, got %d", entry.AccountID, -entry.Amount, totals[entry.AccountID])
+		}
+	}
+}
+
+// reversedAmounts sums reversal legs per original entry, the way
+// GetReversedEntryAmounts does from their reversal_of metadata.
+func reversedAmounts(entries []repo.GetEntriesByTransactionIDRow, legs []domain.EntryRequest) map[uuid.UUID]int64 {
+	reversed := make(map[uuid.UUID]int64)
+	for _, leg := range legs {
+		var metadata struct {
+			ReversalOf uuid.UUID `json:"reversal_of"`
+		}
+		_ = json.Unmarshal(leg.Metadata, &metadata)
+		reversed[metadata.ReversalOf] += leg.Amount
+	}
+	return reversed
+}
+
+func TestReversalLegs_Errors(t *testing.T) {
+	entries := []repo.GetEntriesByTransactionIDRow{
+		entryRow(alice, -100, repo.CurrencyUSD),
+		entryRow(bob, 100, repo.CurrencyUSD),
+	}
+
+	done := map[uuid.UUID]int64{entries[0].ID: 100, entries[1].ID: -100}
+	if _, _, err := reversalLegs(entries, 0, done); !errors.Is(err, ErrAlreadyReversed) {
+		t.Errorf("Expected ErrAlreadyReversed, got %v", err)
+	}
+	partly := map[uuid.UUID]int64{entries[0].ID: 30, entries[1].ID: -30}
+	if _, _, err := reversalLegs(entries, 80, partly); !errors.Is(err, ErrInvalidReversal) {
+		t.Errorf("Expected ErrInvalidReversal, got %v", err)
+	}
+
+	fx := []repo.GetEntriesByTransactionIDRow{
+		entryRow(alice, -100, repo.CurrencyUSD),
+		entryRow(SystemPoolUSD, 100, repo.CurrencyUSD),
+		entryRow(SystemPoolBRL, -533, repo.CurrencyBRL),
+		entryRow(bob, 533, repo.CurrencyBRL),
+	}
+	if _, _, err := reversalLegs(fx, 50, nil); !errors.Is(err, ErrNotReversible) {
+		t.Errorf("Expected ErrNotReversible for a partial multi-currency reversal, got %v", err)
+	}
+}
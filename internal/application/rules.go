@@ -1,13 +1,11 @@
 package application
 
-import "github.com/google/uuid"
+import (
+	"strconv"
+	"strings"
 
-// Fee of 1%
-const GATEWAY_FEE int64 = 1
-
-func CalculateFee(amount int64) int64 {
-	return (amount * GATEWAY_FEE) / 10000
-}
+	"github.com/google/uuid"
+)
 
 type Currency string
 
@@ -16,8 +14,79 @@ const (
 	CurrencyBRL Currency = "BRL"
 )
 
+func (c Currency) IsSupported() bool {
+	switch c {
+	case CurrencyUSD, CurrencyBRL:
+		return true
+	}
+	return false
+}
+
+// Transaction types used to pick the fee schedules
+const (
+	TransactionTypeTransfer   = "transfer"
+	TransactionTypeFXTransfer = "fx_transfer"
+)
+
 // Fixed Systems Pools
 var (
 	SystemPoolUSD = uuid.MustParse("00000000-0000-0000-0000-000000000001")
 	SystemPoolBRL = uuid.MustParse("00000000-0000-0000-0000-000000000002")
 )
+
+// Liquidity pool holding each currency on the house side of a conversion
+var systemPools = map[Currency]uuid.UUID{
+	CurrencyUSD: SystemPoolUSD,
+	CurrencyBRL: SystemPoolBRL,
+}
+
+// Fixed fee revenue accounts
+var (
+	SystemFeeRevenueUSD = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	SystemFeeRevenueBRL = uuid.MustParse("00000000-0000-0000-0000-000000000004")
+)
+
+var feeRevenueAccounts = map[Currency]uuid.UUID{
+	CurrencyUSD: SystemFeeRevenueUSD,
+	CurrencyBRL: SystemFeeRevenueBRL,
+}
+
+// Fixed FX revenue accounts collecting the spread earned on conversions
+var (
+	SystemFXRevenueUSD = uuid.MustParse("00000000-0000-0000-0000-000000000005")
+	SystemFXRevenueBRL = uuid.MustParse("00000000-0000-0000-0000-000000000006")
+)
+
+var fxRevenueAccounts = map[Currency]uuid.UUID{
+	CurrencyUSD: SystemFXRevenueUSD,
+	CurrencyBRL: SystemFXRevenueBRL,
+}
+
+// Number of digits after the decimal point of each currency's minor unit
+var minorUnits = map[Currency]int{
+	CurrencyUSD: 2,
+	CurrencyBRL: 2,
+}
+
+// FormatAmount renders an amount in minor units as a decimal string with the
+// currency's precision, 100000 USD cents becoming "1000.00".
+func FormatAmount(amount int64, currency Currency) string {
+	digits, ok := minorUnits[currency]
+	if !ok || digits == 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+
+	sign := ""
+	magnitude := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		magnitude = -magnitude
+	}
+
+	raw := strconv.FormatUint(magnitude, 10)
+	if len(raw) <= digits {
+		raw = strings.Repeat("0", digits-len(raw)+1) + raw
+	}
+
+	return sign + raw[:len(raw)-digits] + "." + raw[len(raw)-digits:]
+}
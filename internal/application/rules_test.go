@@ -0,0 +1,23 @@
+package application
+
+import "testing"
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency Currency
+		want     string
+	}{
+		{amount: 100000, currency: CurrencyUSD, want: "1000.00"},
+		{amount: 5, currency: CurrencyBRL, want: "0.05"},
+		{amount: 0, currency: CurrencyUSD, want: "0.00"},
+		{amount: -1234, currency: CurrencyUSD, want: "-12.34"},
+		{amount: 42, currency: "JPY", want: "42"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("Expected %d %s to format as %s, got %s", tt.amount, tt.currency, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultSnapshotInterval   = 5 * time.Minute
+	defaultSnapshotMinEntries = 1000
+
+	// Postings commit within this long of their start, as runInTx has
+	// Postgres abort any transaction open for longer than txTimeout.
+	// Sequences are handed out before commit and out of start order, so a
+	// still open posting may hold a lower seq than one already visible, and
+	// a snapshot only covers seqs handed out long enough ago to be final
+	snapshotSettle = time.Minute
+)
+
+// RunBalanceSnapshots snapshots balances on every tick of the configured
+// interval until the context is done. Errors are logged and retried on the
+// next tick.
+func (l *LedgerService) RunBalanceSnapshots(ctx context.Context) {
+	interval := defaultSnapshotInterval
+	if l.cfg.SNAPSHOT_INTERVAL_SECONDS > 0 {
+		interval = time.Duration(l.cfg.SNAPSHOT_INTERVAL_SECONDS) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			taken, err := l.TakeBalanceSnapshots(ctx)
+			if err != nil {
+				slog.Error("error taking balance snapshots",
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+			if taken > 0 {
+				slog.Info("balance snapshots taken", slog.Int64("accounts", taken))
+			}
+		}
+	}
+}
+
+// TakeBalanceSnapshots writes a new snapshot for every account that got at
+// least the configured number of entries since its latest snapshot, and
+// returns how many accounts were snapshotted.
+func (l *LedgerService) TakeBalanceSnapshots(ctx context.Context) (int64, error) {
+	minEntries := int64(defaultSnapshotMinEntries)
+	if l.cfg.SNAPSHOT_MIN_ENTRIES > 0 {
+		minEntries = int64(l.cfg.SNAPSHOT_MIN_ENTRIES)
+	}
+
+	taken, err := l.store.CreateBalanceSnapshots(ctx, repo.CreateBalanceSnapshotsParams{
+		Settle:     pgtype.Interval{Microseconds: snapshotSettle.Microseconds(), Valid: true},
+		MinEntries: minEntries,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error creating balance snapshots: %w", err)
+	}
+
+	return taken, nil
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// bpsDenominator is one whole in basis points
+const bpsDenominator = 10000
+
+type spreadTier struct {
+	// UpTo is the inclusive upper bound of the tier, nil for the last one
+	UpTo *int64 `json:"up_to"`
+	Bps  int32  `json:"bps"`
+}
+
+// SpreadRule is the spread of one currency pair, the basis points taken off
+// the mid rate, flat or tiered by the amount converted.
+type SpreadRule struct {
+	ID    uuid.UUID
+	Bps   int32
+	Tiers []spreadTier
+}
+
+// For returns the spread in basis points for converting amount. Tiers win
+// over the flat spread, an amount above every tier gets the flat one.
+func (r SpreadRule) For(amount int64) int32 {
+	for _, tier := range r.Tiers {
+		if tier.UpTo == nil || amount <= *tier.UpTo {
+			return tier.Bps
+		}
+	}
+	return r.Bps
+}
+
+func newSpreadRule(spread repo.FxSpread) (SpreadRule, error) {
+	rule := SpreadRule{ID: spread.ID, Bps: spread.Bps}
+
+	if err := json.Unmarshal(spread.Tiers, &rule.Tiers); err != nil {
+		return SpreadRule{}, fmt.Errorf("error decoding tiers of fx spread %s: %w", spread.ID, err)
+	}
+	// Tiers are matched in order, so their bounds must go up and only the
+	// last one may be open
+	for i, tier := range rule.Tiers {
+		if tier.Bps < 0 || tier.Bps >= bpsDenominator {
+			return SpreadRule{}, fmt.Errorf("fx spread %s has a tier of %d bps", spread.ID, tier.Bps)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := rule.Tiers[i-1].UpTo
+		if previous == nil {
+			return SpreadRule{}, fmt.Errorf("fx spread %s has a tier after the open one", spread.ID)
+		}
+		if tier.UpTo != nil && *tier.UpTo <= *previous {
+			return SpreadRule{}, fmt.Errorf("fx spread %s has tier bounds out of order at %d", spread.ID, *tier.UpTo)
+		}
+	}
+
+	return rule, nil
+}
+
+// spreadFor returns the spread to apply when converting amount over pair:
+// the active spread of the pair or, when it has none, the configured default.
+func (l *LedgerService) spreadFor(ctx context.Context, pair fx.Pair, amount int64) (int32, error) {
+	spread, err := l.store.GetActiveFxSpread(ctx, repo.GetActiveFxSpreadParams{
+		Base:  repo.Currency(pair.Base),
+		Quote: repo.Currency(pair.Quote),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		if l.cfg.FX_SPREAD_BPS > 0 && l.cfg.FX_SPREAD_BPS < bpsDenominator {
+			return int32(l.cfg.FX_SPREAD_BPS), nil
+		}
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error fetching the fx spread of %s: %w", pair, err)
+	}
+
+	rule, err := newSpreadRule(spread)
+	if err != nil {
+		return 0, err
+	}
+	return rule.For(amount), nil
+}
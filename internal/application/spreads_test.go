@@ -0,0 +1,54 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+)
+
+func TestSpreadRule(t *testing.T) {
+	flat, err := newSpreadRule(repo.FxSpread{ID: uuid.New(), Bps: 50, Tiers: []byte("[]")})
+	if err != nil {
+		t.Fatalf("newSpreadRule returned error: %v", err)
+	}
+
+	tiered, err := newSpreadRule(repo.FxSpread{
+		ID:    uuid.New(),
+		Bps:   25,
+		Tiers: []byte(`[{"up_to": 100000, "bps": 150}, {"up_to": 1000000, "bps": 75}]`),
+	})
+	if err != nil {
+		t.Fatalf("newSpreadRule returned error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		rule   SpreadRule
+		amount int64
+		want   int32
+	}{
+		{name: "flat", rule: flat, amount: 500000, want: 50},
+		{name: "first tier", rule: tiered, amount: 100000, want: 150},
+		{name: "second tier", rule: tiered, amount: 100001, want: 75},
+		{name: "above every tier", rule: tiered, amount: 5000000, want: 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.For(tt.amount); got != tt.want {
+				t.Errorf("For(%d) = %d, want %d", tt.amount, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newSpreadRule(repo.FxSpread{ID: uuid.New(), Tiers: []byte(`[{"up_to": null, "bps": 10000}]`)}); err == nil {
+		t.Error("Expected a tier taking the whole rate to be rejected")
+	}
+	if _, err := newSpreadRule(repo.FxSpread{ID: uuid.New(), Tiers: []byte(`[{"up_to": 1000000, "bps": 75}, {"up_to": 100000, "bps": 150}]`)}); err == nil {
+		t.Error("Expected tiers out of order to be rejected")
+	}
+	if _, err := newSpreadRule(repo.FxSpread{ID: uuid.New(), Tiers: []byte(`[{"up_to": null, "bps": 50}, {"up_to": 100000, "bps": 150}]`)}); err == nil {
+		t.Error("Expected a tier after the open one to be rejected")
+	}
+}
@@ -0,0 +1,79 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetAccountStatement returns a page of the entries of an account, newest
+// first, each with the balance of the account right after it. Pages are
+// keyset paginated on (created_at, id) so new entries never shift them. The
+// running balance is worked back from the materialized balance, so a page
+// only sums the entries newer than it instead of the whole history.
+func (l *LedgerService) GetAccountStatement(ctx context.Context, id uuid.UUID, filter domain.StatementFilter) (*domain.Statement, error) {
+	if _, err := l.GetAccount(ctx, id); err != nil {
+		return nil, err
+	}
+
+	limit := pageSize(filter.Limit)
+	params := repo.GetAccountStatementParams{
+		AccountID: id,
+		FromDate:  pgtype.Timestamptz{Time: filter.From, Valid: !filter.From.IsZero()},
+		ToDate:    pgtype.Timestamptz{Time: filter.To, Valid: !filter.To.IsZero()},
+		MinAmount: optionalInt8(filter.MinAmount),
+		MaxAmount: optionalInt8(filter.MaxAmount),
+		// One extra row tells whether there is a next page
+		PageSize: int32(limit + 1),
+	}
+
+	if filter.Cursor != "" {
+		createdAt, entryID, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: createdAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: entryID, Valid: true}
+	}
+
+	rows, err := l.store.GetAccountStatement(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching statement of account %s: %w", id, err)
+	}
+
+	statement := &domain.Statement{Lines: []domain.StatementLine{}}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[limit-1]
+		statement.NextCursor = encodeCursor(last.CreatedAt.Time, last.ID)
+	}
+
+	for _, row := range rows {
+		statement.Lines = append(statement.Lines, domain.StatementLine{
+			EntryID:          row.ID,
+			TransactionID:    row.TransactionID,
+			Amount:           row.Amount,
+			Currency:         string(row.Currency),
+			Metadata:         row.Metadata,
+			CreatedAt:        row.CreatedAt.Time,
+			EffectiveAt:      row.EffectiveAt.Time,
+			RunningBalance:   row.RunningBalance,
+			Description:      row.Description.String,
+			CounterpartyID:   row.CounterpartyID.UUID,
+			CounterpartyName: row.CounterpartyName.String,
+		})
+	}
+
+	return statement, nil
+}
+
+func optionalInt8(v *int64) pgtype.Int8 {
+	if v == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: *v, Valid: true}
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetTransaction returns a transaction header with all of its entries.
+func (l *LedgerService) GetTransaction(ctx context.Context, id uuid.UUID) (*domain.TransactionResult, error) {
+	header, err := l.store.GetTransaction(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, id)
+		}
+		return nil, fmt.Errorf("error fetching transaction %s: %w", id, err)
+	}
+
+	entries, err := l.store.GetEntriesByTransactionID(ctx, header.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching entries for transaction %s: %w", header.ID, err)
+	}
+
+	result := newTransactionResult(header)
+	for _, entry := range entries {
+		result.Entries = append(result.Entries, domainEntry(repo.CreateEntryRow(entry)))
+	}
+
+	return result, nil
+}
+
+// SearchTransactions returns a page of transaction headers matching the
+// filter, newest first. Entries are left out, GetTransaction has them.
+func (l *LedgerService) SearchTransactions(ctx context.Context, filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	if len(filter.Metadata) > 0 {
+		var metadata map[string]json.RawMessage
+		if err := json.Unmarshal(filter.Metadata, &metadata); err != nil || metadata == nil {
+			return nil, fmt.Errorf("%w: metadata filter must be a JSON object", ErrInvalidTransaction)
+		}
+	}
+
+	limit := pageSize(filter.Limit)
+	params := repo.SearchTransactionsParams{
+		Status:     pgtype.Text{String: filter.Status, Valid: filter.Status != ""},
+		ExternalID: pgtype.Text{String: filter.ExternalID, Valid: filter.ExternalID != ""},
+		CreatedBy:  pgtype.Text{String: filter.CreatedBy, Valid: filter.CreatedBy != ""},
+		FromDate:   pgtype.Timestamptz{Time: filter.From, Valid: !filter.From.IsZero()},
+		ToDate:     pgtype.Timestamptz{Time: filter.To, Valid: !filter.To.IsZero()},
+		AccountID:  uuid.NullUUID{UUID: filter.AccountID, Valid: filter.AccountID != uuid.Nil},
+		Metadata:   filter.Metadata,
+		PageSize:   int32(limit + 1),
+	}
+
+	if filter.Cursor != "" {
+		createdAt, id, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: createdAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	headers, err := l.store.SearchTransactions(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error searching transactions: %w", err)
+	}
+
+	page := &domain.TransactionPage{Transactions: []domain.TransactionResult{}}
+	if len(headers) > limit {
+		headers = headers[:limit]
+		last := headers[limit-1]
+		page.NextCursor = encodeCursor(last.CreatedAt.Time, last.ID)
+	}
+
+	for _, header := range headers {
+		page.Transactions = append(page.Transactions, *newTransactionResult(header))
+	}
+
+	return page, nil
+}
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/domain"
+	"github.com/IgorGrieder/Small-Ledger/internal/repo"
+)
+
+// Verifier checks the invariants the ledger promises against the stored
+// transactions and entries. It only reads, so it can run against a live
+// database or a restored backup.
+type Verifier struct {
+	store *repo.SQLStore
+}
+
+func NewVerifier(store *repo.SQLStore) *Verifier {
+	return &Verifier{store: store}
+}
+
+// Verify runs every check inside a single read only repeatable read
+// transaction, so postings committed while it runs can not show up as drift.
+func (v *Verifier) Verify(ctx context.Context) (domain.IntegrityReport, error) {
+	tx, err := v.store.CreateTx(ctx)
+	if err != nil {
+		return domain.IntegrityReport{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY"); err != nil {
+		return domain.IntegrityReport{}, fmt.Errorf("error setting the isolation level: %w", err)
+	}
+
+	qtx := v.store.WithTx(tx)
+	report := domain.IntegrityReport{CheckedAt: time.Now()}
+
+	unbalanced, err := qtx.GetUnbalancedTransactions(ctx)
+	if err != nil {
+		return domain.IntegrityReport{}, fmt.Errorf("error checking transaction balances: %w", err)
+	}
+	for _, row := range unbalanced {
+		report.UnbalancedTransactions = append(report.UnbalancedTransactions, domain.UnbalancedTransaction{
+			TransactionID: row.TransactionID,
+			Currency:      string(row.Currency),
+			Total:         row.Total,
+		})
+	}
+
+	mismatches, err := qtx.GetEntryCurrencyMismatches(ctx)
+	if err != nil {
+		return domain.IntegrityReport{}, fmt.Errorf("error checking entry currencies: %w", err)
+	}
+	for _, row := range mismatches {
+		report.CurrencyMismatches = append(report.CurrencyMismatches, domain.CurrencyMismatch{
+			EntryID:         row.EntryID,
+			TransactionID:   row.TransactionID,
+			AccountID:       row.AccountID,
+			EntryCurrency:   string(row.EntryCurrency),
+			AccountCurrency: string(row.AccountCurrency),
+		})
+	}
+
+	orphans, err := qtx.GetOrphanTransactions(ctx)
+	if err != nil {
+		return domain.IntegrityReport{}, fmt.Errorf("error checking orphan transactions: %w", err)
+	}
+	for _, row := range orphans {
+		report.OrphanTransactions = append(report.OrphanTransactions, domain.OrphanTransaction{
+			TransactionID: row.ID,
+			ExternalID:    row.ExternalID.String,
+			Status:        row.Status,
+			CreatedAt:     row.CreatedAt.Time,
+		})
+	}
+
+	drifts, err := qtx.GetBalanceDrift(ctx)
+	if err != nil {
+		return domain.IntegrityReport{}, fmt.Errorf("error computing balance drift: %w", err)
+	}
+	for _, row := range drifts {
+		report.BalanceDrifts = append(report.BalanceDrifts, domain.BalanceDrift{
+			AccountID:    row.AccountID,
+			Currency:     string(row.Currency),
+			Materialized: row.Materialized,
+			Computed:     row.Computed,
+		})
+	}
+
+	snapshots, err := qtx.GetSnapshotDrift(ctx)
+	if err != nil {
+		return domain.IntegrityReport{}, fmt.Errorf("error checking balance snapshots: %w", err)
+	}
+	for _, row := range snapshots {
+		report.SnapshotDrifts = append(report.SnapshotDrifts, domain.SnapshotDrift{
+			AccountID: row.AccountID,
+			Currency:  string(row.Currency),
+			UpToSeq:   row.UpToSeq,
+			Snapshot:  row.Snapshot,
+			Computed:  row.Computed,
+		})
+	}
+
+	duplicates, err := qtx.GetDuplicateExternalIDs(ctx)
+	if err != nil {
+		return domain.IntegrityReport{}, fmt.Errorf("error checking external ids: %w", err)
+	}
+	for _, row := range duplicates {
+		report.DuplicateExternalIDs = append(report.DuplicateExternalIDs, domain.DuplicateExternalID{
+			ExternalID:     row.ExternalID,
+			TransactionIDs: row.TransactionIds,
+		})
+	}
+
+	return report, nil
+}
@@ -17,6 +17,17 @@ type Config struct {
 	PG_DB_NAME       string
 	PG_PASS          string
 	CURRENCY_URL     string
+	FX_RATES_FILE    string
+
+	FX_QUOTE_TTL_SECONDS int
+	FX_SPREAD_BPS        int
+
+	SNAPSHOT_INTERVAL_SECONDS int
+	SNAPSHOT_MIN_ENTRIES      int
+	HOLD_TTL_SECONDS          int
+
+	CHAIN_INTERVAL_SECONDS       int
+	ATTESTATION_INTERVAL_SECONDS int
 }
 
 func NewConfig() *Config {
@@ -31,6 +42,14 @@ func NewConfig() *Config {
 	dbname := getEnv("PG_DB")
 	pgPass := getEnv("PG_PASS")
 	currencyUrl := getEnv("CURRENCY_URL")
+	fxRatesFile := getEnv("FX_RATES_FILE")
+	fxQuoteTTL := parseInt(getEnv("FX_QUOTE_TTL_SECONDS"))
+	fxSpread := parseInt(getEnv("FX_SPREAD_BPS"))
+	snapshotInterval := parseInt(getEnv("SNAPSHOT_INTERVAL_SECONDS"))
+	snapshotMinEntries := parseInt(getEnv("SNAPSHOT_MIN_ENTRIES"))
+	holdTTL := parseInt(getEnv("HOLD_TTL_SECONDS"))
+	chainInterval := parseInt(getEnv("CHAIN_INTERVAL_SECONDS"))
+	attestationInterval := parseInt(getEnv("ATTESTATION_INTERVAL_SECONDS"))
 
 	return &Config{
 		APPLICATION_PORT: port,
@@ -42,6 +61,17 @@ func NewConfig() *Config {
 		PG_DB_NAME:       dbname,
 		PG_PASS:          pgPass,
 		CURRENCY_URL:     currencyUrl,
+		FX_RATES_FILE:    fxRatesFile,
+
+		FX_QUOTE_TTL_SECONDS: fxQuoteTTL,
+		FX_SPREAD_BPS:        fxSpread,
+
+		SNAPSHOT_INTERVAL_SECONDS: snapshotInterval,
+		SNAPSHOT_MIN_ENTRIES:      snapshotMinEntries,
+		HOLD_TTL_SECONDS:          holdTTL,
+
+		CHAIN_INTERVAL_SECONDS:       chainInterval,
+		ATTESTATION_INTERVAL_SECONDS: attestationInterval,
 	}
 }
 
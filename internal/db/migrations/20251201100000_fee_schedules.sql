@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE fee_schedules (
+    id UUID PRIMARY KEY,
+    transaction_type TEXT NOT NULL,
+    currency currency NOT NULL,
+    kind TEXT NOT NULL,
+    bps BIGINT NOT NULL DEFAULT 0,
+    flat_amount BIGINT NOT NULL DEFAULT 0,
+    min_amount BIGINT,
+    max_amount BIGINT,
+    tiers JSONB NOT NULL DEFAULT '[]'::jsonb,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX fee_schedules_lookup_idx ON fee_schedules (transaction_type, currency) WHERE active;
+
+-- Fee revenue accounts collecting every fee leg
+INSERT INTO accounts (id, name, currency, metadata) VALUES
+('00000000-0000-0000-0000-000000000003', 'Fee Revenue USD', 'USD', '{"type": "revenue"}'),
+('00000000-0000-0000-0000-000000000004', 'Fee Revenue BRL', 'BRL', '{"type": "revenue"}');
+
+-- Gateway fee of 1% on transfers
+INSERT INTO fee_schedules (id, transaction_type, currency, kind, bps) VALUES
+(gen_random_uuid(), 'transfer', 'USD', 'percentage', 100),
+(gen_random_uuid(), 'transfer', 'BRL', 'percentage', 100),
+(gen_random_uuid(), 'fx_transfer', 'USD', 'percentage', 100),
+(gen_random_uuid(), 'fx_transfer', 'BRL', 'percentage', 100);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE fee_schedules;
+-- +goose StatementEnd
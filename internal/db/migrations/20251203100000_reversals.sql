@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE transactions
+    ADD COLUMN reverses_transaction_id UUID REFERENCES transactions(id) ON DELETE RESTRICT;
+
+CREATE INDEX transactions_reverses_idx ON transactions (reverses_transaction_id)
+    WHERE reverses_transaction_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS transactions_reverses_idx;
+ALTER TABLE transactions DROP COLUMN reverses_transaction_id;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE account_balances (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE RESTRICT,
+    currency currency NOT NULL,
+    balance BIGINT NOT NULL DEFAULT 0,
+    version BIGINT NOT NULL DEFAULT 0,
+    last_entry_id UUID REFERENCES entries(id) ON DELETE RESTRICT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Backfill from the ledger, the source of truth
+INSERT INTO account_balances (account_id, currency, balance, version)
+SELECT accounts.id, accounts.currency, COALESCE(SUM(entries.amount), 0)::BIGINT, COUNT(entries.id)
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id
+GROUP BY accounts.id, accounts.currency;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE account_balances;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE accounts
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'open',
+    ADD COLUMN closed_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE accounts
+    DROP COLUMN closed_at,
+    DROP COLUMN status;
+-- +goose StatementEnd
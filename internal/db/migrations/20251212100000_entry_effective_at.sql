@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE entries ADD COLUMN effective_at TIMESTAMPTZ;
+UPDATE entries SET effective_at = created_at;
+ALTER TABLE entries ALTER COLUMN effective_at SET NOT NULL;
+ALTER TABLE entries ALTER COLUMN effective_at SET DEFAULT NOW();
+
+CREATE INDEX entries_account_effective_at_idx ON entries (account_id, effective_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX entries_account_effective_at_idx;
+ALTER TABLE entries DROP COLUMN effective_at;
+-- +goose StatementEnd
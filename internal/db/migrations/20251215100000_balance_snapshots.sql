@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Insertion order of the entries, snapshots cover every entry up to a seq
+ALTER TABLE entries ADD COLUMN seq BIGSERIAL;
+CREATE UNIQUE INDEX entries_account_seq_idx ON entries (account_id, seq);
+
+CREATE TABLE balance_snapshots (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    currency currency NOT NULL,
+    balance BIGINT NOT NULL,                       -- sum of the entries up to up_to_seq
+    up_to_seq BIGINT NOT NULL,
+    taken_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, up_to_seq)
+);
+
+CREATE INDEX balance_snapshots_account_taken_at_idx ON balance_snapshots (account_id, taken_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE balance_snapshots;
+DROP INDEX entries_account_seq_idx;
+ALTER TABLE entries DROP COLUMN seq;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE transactions ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE INDEX transactions_created_at_idx ON transactions (created_at, id);
+CREATE INDEX transactions_created_by_idx ON transactions (created_by);
+CREATE INDEX transactions_metadata_idx ON transactions USING GIN (metadata jsonb_path_ops);
+CREATE INDEX entries_transaction_id_idx ON entries (transaction_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX entries_transaction_id_idx;
+DROP INDEX transactions_metadata_idx;
+DROP INDEX transactions_created_by_idx;
+DROP INDEX transactions_created_at_idx;
+ALTER TABLE transactions DROP COLUMN metadata;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE accounts
+    ADD COLUMN balance_policy TEXT NOT NULL DEFAULT 'non_negative'
+        CHECK (balance_policy IN ('non_negative', 'overdraft', 'unlimited')),
+    ADD COLUMN overdraft_limit BIGINT NOT NULL DEFAULT 0
+        CHECK (overdraft_limit >= 0);
+
+-- House accounts fund everything else and are expected to go negative
+UPDATE accounts SET balance_policy = 'unlimited'
+WHERE metadata->>'type' IN ('system', 'equity');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE accounts DROP COLUMN overdraft_limit, DROP COLUMN balance_policy;
+-- +goose StatementEnd
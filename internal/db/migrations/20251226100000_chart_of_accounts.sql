@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TYPE account_class AS ENUM ('asset', 'liability', 'equity', 'revenue', 'expense');
+
+ALTER TABLE accounts
+    ADD COLUMN account_class account_class NOT NULL DEFAULT 'liability',
+    ADD COLUMN normal_balance TEXT NOT NULL DEFAULT 'credit'
+        CHECK (normal_balance IN ('debit', 'credit')),
+    ADD COLUMN parent_id UUID REFERENCES accounts(id) ON DELETE RESTRICT;
+
+CREATE INDEX accounts_parent_id_idx ON accounts (parent_id);
+
+-- Classes from the free-form type the accounts were seeded with. Entry
+-- amounts are credits when positive and debits when negative, so a class must
+-- match the side the account's balance sits on. The World Bank accounts are
+-- debited for all the money that enters the ledger: they are the funds held
+-- at the bank, an asset. The pools are credited with the capital the house
+-- put up for conversions, which is equity
+UPDATE accounts SET account_class = CASE metadata->>'type'
+    WHEN 'system' THEN 'equity'::account_class
+    WHEN 'equity' THEN 'asset'::account_class
+    WHEN 'revenue' THEN 'revenue'::account_class
+    ELSE 'liability'::account_class
+END;
+
+UPDATE accounts SET normal_balance = 'debit'
+WHERE account_class IN ('asset', 'expense');
+
+-- Group accounts every customer wallet rolls up into
+INSERT INTO accounts (id, name, currency, metadata, account_class, normal_balance) VALUES
+('00000000-0000-0000-0000-000000000007', 'Customer Wallets USD', 'USD', '{"type": "group"}', 'liability', 'credit'),
+('00000000-0000-0000-0000-000000000008', 'Customer Wallets BRL', 'BRL', '{"type": "group"}', 'liability', 'credit');
+
+INSERT INTO account_balances (account_id, currency) VALUES
+('00000000-0000-0000-0000-000000000007', 'USD'),
+('00000000-0000-0000-0000-000000000008', 'BRL');
+
+UPDATE accounts SET parent_id = CASE currency
+    WHEN 'USD' THEN '00000000-0000-0000-0000-000000000007'::uuid
+    WHEN 'BRL' THEN '00000000-0000-0000-0000-000000000008'::uuid
+END
+WHERE metadata->>'type' = 'user';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+UPDATE accounts SET parent_id = NULL;
+DELETE FROM account_balances WHERE account_id IN ('00000000-0000-0000-0000-000000000007', '00000000-0000-0000-0000-000000000008');
+DELETE FROM accounts WHERE id IN ('00000000-0000-0000-0000-000000000007', '00000000-0000-0000-0000-000000000008');
+ALTER TABLE accounts DROP COLUMN parent_id, DROP COLUMN normal_balance, DROP COLUMN account_class;
+DROP TYPE account_class;
+-- +goose StatementEnd
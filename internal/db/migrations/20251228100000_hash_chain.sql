@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE transactions ADD COLUMN hash BYTEA;
+ALTER TABLE transactions ADD COLUMN prev_hash BYTEA;
+ALTER TABLE transactions ADD COLUMN chain_seq BIGINT;
+
+CREATE UNIQUE INDEX transactions_chain_seq_idx ON transactions (chain_seq);
+CREATE INDEX transactions_unchained_idx ON transactions (created_at, id) WHERE chain_seq IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX transactions_unchained_idx;
+DROP INDEX transactions_chain_seq_idx;
+ALTER TABLE transactions DROP COLUMN chain_seq;
+ALTER TABLE transactions DROP COLUMN prev_hash;
+ALTER TABLE transactions DROP COLUMN hash;
+-- +goose StatementEnd
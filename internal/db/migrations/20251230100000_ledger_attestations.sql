@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE transactions ADD COLUMN linked_at TIMESTAMPTZ;
+
+CREATE INDEX transactions_linked_at_idx ON transactions (linked_at);
+
+CREATE TABLE ledger_attestations (
+    day DATE PRIMARY KEY,
+    root BYTEA NOT NULL,
+    leaf_count BIGINT NOT NULL,
+    first_chain_seq BIGINT NOT NULL,
+    last_chain_seq BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE ledger_attestations;
+DROP INDEX transactions_linked_at_idx;
+ALTER TABLE transactions DROP COLUMN linked_at;
+-- +goose StatementEnd
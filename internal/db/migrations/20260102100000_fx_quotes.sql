@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE fx_quotes (
+    id UUID PRIMARY KEY,
+    source_currency currency NOT NULL,
+    target_currency currency NOT NULL,
+    source_amount BIGINT NOT NULL CHECK (source_amount > 0),
+    target_amount BIGINT NOT NULL CHECK (target_amount > 0),
+    mid_rate NUMERIC(38,18) NOT NULL,
+    spread_bps INTEGER NOT NULL DEFAULT 0,
+    rate NUMERIC(38,18) NOT NULL,
+    rate_source TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    transaction_id UUID UNIQUE REFERENCES transactions(id) ON DELETE RESTRICT,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (source_currency <> target_currency)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE fx_quotes;
+-- +goose StatementEnd
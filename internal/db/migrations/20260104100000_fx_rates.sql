@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE fx_rates (
+    id UUID PRIMARY KEY,
+    base currency NOT NULL,
+    quote currency NOT NULL,
+    rate NUMERIC(38,18) NOT NULL CHECK (rate > 0),
+    source TEXT NOT NULL,
+    as_of TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX fx_rates_pair_source_as_of_idx ON fx_rates (base, quote, source, as_of);
+CREATE INDEX fx_rates_pair_as_of_idx ON fx_rates (base, quote, as_of);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE fx_rates;
+-- +goose StatementEnd
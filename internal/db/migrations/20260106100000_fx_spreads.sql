@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE fx_spreads (
+    id UUID PRIMARY KEY,
+    base currency NOT NULL,
+    quote currency NOT NULL,
+    bps INTEGER NOT NULL DEFAULT 0 CHECK (bps >= 0 AND bps < 10000),
+    tiers JSONB NOT NULL DEFAULT '[]'::jsonb,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (base <> quote)
+);
+
+CREATE UNIQUE INDEX fx_spreads_active_pair_idx ON fx_spreads (base, quote) WHERE active;
+
+-- FX revenue accounts collecting the spread earned on every conversion
+INSERT INTO accounts (id, name, currency, metadata, account_class, normal_balance) VALUES
+('00000000-0000-0000-0000-000000000005', 'FX Revenue USD', 'USD', '{"type": "revenue"}', 'revenue', 'credit'),
+('00000000-0000-0000-0000-000000000006', 'FX Revenue BRL', 'BRL', '{"type": "revenue"}', 'revenue', 'credit');
+
+INSERT INTO account_balances (account_id, currency) VALUES
+('00000000-0000-0000-0000-000000000005', 'USD'),
+('00000000-0000-0000-0000-000000000006', 'BRL');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DELETE FROM account_balances WHERE account_id IN ('00000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000006');
+DELETE FROM accounts WHERE id IN ('00000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000006');
+DROP TABLE fx_spreads;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Lets the snapshot job find its settled seq from the newest entries back
+CREATE INDEX entries_seq_idx ON entries (seq);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX entries_seq_idx;
+-- +goose StatementEnd
@@ -7,5 +7,494 @@ SELECT * from accounts;
 -- name: GetAllEntries :many
 SELECT * from entries;
 
--- name: GetUserFunds :one
-SELECT COALESCE(SUM(entries.amount), 0)::BIGINT as Funds from entries where account_id = $1 FOR UPDATE;
+-- name: CreateTransaction :one
+INSERT INTO transactions (id, external_id, description, status, created_by, reverses_transaction_id, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (external_id) DO NOTHING
+RETURNING *;
+
+-- name: CreateEntry :one
+INSERT INTO entries (id, transaction_id, account_id, amount, currency, metadata, effective_at)
+VALUES ($1, $2, $3, $4::BIGINT, $5, $6, COALESCE(sqlc.narg(effective_at)::TIMESTAMPTZ, NOW()))
+RETURNING id, transaction_id, account_id, amount::BIGINT AS amount, currency, metadata, created_at, effective_at;
+
+-- name: GetTransactionByExternalID :one
+SELECT * FROM transactions WHERE external_id = $1;
+
+-- name: GetEntriesByTransactionID :many
+SELECT id, transaction_id, account_id, amount::BIGINT AS amount, currency, metadata, created_at, effective_at
+FROM entries
+WHERE transaction_id = $1
+ORDER BY amount, id;
+
+-- name: GetAccountsByIDs :many
+SELECT * FROM accounts WHERE id = ANY(sqlc.arg(ids)::UUID[]);
+
+-- name: GetActiveFeeSchedules :many
+SELECT * FROM fee_schedules
+WHERE active AND transaction_type = $1 AND currency = $2
+ORDER BY created_at, id;
+
+-- name: GetTransactionForUpdate :one
+SELECT * FROM transactions WHERE id = $1 FOR UPDATE;
+
+-- name: UpdateTransactionStatus :exec
+UPDATE transactions SET status = $2 WHERE id = $1;
+
+-- name: GetReversedEntryAmounts :many
+SELECT (entries.metadata->>'reversal_of')::UUID AS entry_id,
+       SUM(entries.amount)::BIGINT AS reversed
+FROM entries
+JOIN transactions ON transactions.id = entries.transaction_id
+WHERE transactions.reverses_transaction_id = $1
+GROUP BY entries.metadata->>'reversal_of';
+
+-- name: ApplyBalanceDelta :exec
+INSERT INTO account_balances (account_id, currency, balance, version, last_entry_id, updated_at)
+VALUES ($1, $2, $3, 1, $4, NOW())
+ON CONFLICT (account_id) DO UPDATE
+SET balance = account_balances.balance + EXCLUDED.balance,
+    version = account_balances.version + 1,
+    last_entry_id = EXCLUDED.last_entry_id,
+    updated_at = NOW();
+
+-- name: GetAccountBalanceForUpdate :one
+SELECT (balance - pending)::BIGINT AS available FROM account_balances WHERE account_id = $1 FOR UPDATE;
+
+-- name: GetBalanceDrift :many
+SELECT accounts.id AS account_id,
+       accounts.currency,
+       COALESCE(account_balances.balance, 0)::BIGINT AS materialized,
+       COALESCE(sums.total, 0)::BIGINT AS computed
+FROM accounts
+LEFT JOIN account_balances ON account_balances.account_id = accounts.id
+LEFT JOIN (
+    SELECT account_id, SUM(amount) AS total FROM entries GROUP BY account_id
+) sums ON sums.account_id = accounts.id
+WHERE COALESCE(account_balances.balance, 0) <> COALESCE(sums.total, 0);
+
+-- name: GetSnapshotDrift :many
+SELECT latest.account_id,
+       latest.currency,
+       latest.up_to_seq,
+       latest.balance AS snapshot,
+       COALESCE((SELECT SUM(amount) FROM entries
+                 WHERE entries.account_id = latest.account_id
+                   AND entries.seq <= latest.up_to_seq), 0)::BIGINT AS computed
+FROM (
+    SELECT DISTINCT ON (account_id) account_id, currency, balance, up_to_seq
+    FROM balance_snapshots
+    ORDER BY account_id, up_to_seq DESC
+) latest
+WHERE latest.balance <> COALESCE((SELECT SUM(amount) FROM entries
+                                  WHERE entries.account_id = latest.account_id
+                                    AND entries.seq <= latest.up_to_seq), 0);
+
+-- name: CreateAccount :one
+INSERT INTO accounts (id, name, currency, metadata, balance_policy, overdraft_limit, account_class, normal_balance, parent_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: CreateAccountBalance :exec
+INSERT INTO account_balances (account_id, currency) VALUES ($1, $2);
+
+-- name: GetAccount :one
+SELECT * FROM accounts WHERE id = $1;
+
+-- name: GetAccountForUpdate :one
+SELECT * FROM accounts WHERE id = $1 FOR NO KEY UPDATE;
+
+-- name: UpdateAccountMetadata :one
+UPDATE accounts
+SET metadata = COALESCE(metadata, '{}'::jsonb) || sqlc.arg(metadata)::jsonb
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CloseAccount :one
+UPDATE accounts SET status = 'closed', closed_at = NOW() WHERE id = $1 RETURNING *;
+
+-- name: GetAccountBalances :many
+SELECT accounts.id AS account_id,
+       accounts.currency,
+       COALESCE(account_balances.balance, 0)::BIGINT AS balance,
+       COALESCE(account_balances.pending, 0)::BIGINT AS pending
+FROM accounts
+LEFT JOIN account_balances ON account_balances.account_id = accounts.id
+WHERE accounts.id = ANY(sqlc.arg(ids)::UUID[]);
+
+-- name: GetAccountBalanceAsOf :one
+WITH snapshot AS (
+    SELECT balance, up_to_seq FROM balance_snapshots
+    WHERE account_id = sqlc.arg(account_id) AND taken_at <= sqlc.arg(as_of)
+    ORDER BY up_to_seq DESC
+    LIMIT 1
+)
+SELECT accounts.currency,
+       (COALESCE((SELECT balance FROM snapshot), 0)
+        + COALESCE((SELECT SUM(amount) FROM entries
+                    WHERE account_id = accounts.id
+                      AND seq > COALESCE((SELECT up_to_seq FROM snapshot), 0)
+                      AND effective_at <= sqlc.arg(as_of)), 0)
+        - COALESCE((SELECT SUM(amount) FROM entries
+                    WHERE account_id = accounts.id
+                      AND seq <= COALESCE((SELECT up_to_seq FROM snapshot), 0)
+                      AND effective_at > sqlc.arg(as_of)), 0))::BIGINT AS balance
+FROM accounts
+WHERE accounts.id = sqlc.arg(account_id);
+
+-- name: CreateBalanceSnapshots :execrows
+WITH settled AS (
+    -- A posting commits within settle of its start, so every seq handed out
+    -- before an entry whose transaction started two settle periods ago is
+    -- final, whatever the order in which the postings started
+    SELECT seq FROM entries
+    WHERE created_at < NOW() - 2 * sqlc.arg(settle)::INTERVAL
+    ORDER BY seq DESC
+    LIMIT 1
+)
+INSERT INTO balance_snapshots (account_id, currency, balance, up_to_seq)
+SELECT entries.account_id,
+       accounts.currency,
+       (COALESCE(last.balance, 0) + SUM(entries.amount))::BIGINT,
+       MAX(entries.seq)
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+LEFT JOIN LATERAL (
+    SELECT balance, up_to_seq FROM balance_snapshots
+    WHERE balance_snapshots.account_id = entries.account_id
+    ORDER BY up_to_seq DESC
+    LIMIT 1
+) last ON TRUE
+WHERE entries.seq > COALESCE(last.up_to_seq, 0)
+  AND entries.seq <= (SELECT seq FROM settled)
+GROUP BY entries.account_id, accounts.currency, last.balance
+HAVING COUNT(*) >= sqlc.arg(min_entries)::BIGINT;
+
+-- name: GetAccountStatement :many
+WITH start AS (
+    -- The balance right after the newest entry of the page: the current
+    -- balance less every entry at or after the cursor, or the end date
+    SELECT (COALESCE((SELECT balance FROM account_balances WHERE account_id = sqlc.arg(account_id)), 0)
+            - COALESCE((SELECT SUM(amount) FROM entries
+                        WHERE account_id = sqlc.arg(account_id)
+                          AND CASE WHEN sqlc.narg(cursor_created_at)::TIMESTAMPTZ IS NOT NULL
+                                   THEN (created_at, id) >= (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::UUID)
+                                   ELSE created_at >= sqlc.narg(to_date)::TIMESTAMPTZ
+                              END), 0))::BIGINT AS balance
+),
+ledger AS (
+    SELECT entries.id,
+           entries.transaction_id,
+           entries.amount::BIGINT AS amount,
+           entries.currency,
+           entries.metadata,
+           entries.created_at,
+           entries.effective_at,
+           ((SELECT balance FROM start)
+            - COALESCE(SUM(entries.amount) OVER (
+                ORDER BY entries.created_at DESC, entries.id DESC
+                ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0))::BIGINT AS running_balance
+    FROM entries
+    WHERE entries.account_id = sqlc.arg(account_id)
+      AND (sqlc.narg(cursor_created_at)::TIMESTAMPTZ IS NULL
+           OR (entries.created_at, entries.id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::UUID))
+      AND (sqlc.narg(to_date)::TIMESTAMPTZ IS NULL OR entries.created_at < sqlc.narg(to_date))
+      AND (sqlc.narg(from_date)::TIMESTAMPTZ IS NULL OR entries.created_at >= sqlc.narg(from_date))
+)
+SELECT ledger.id,
+       ledger.transaction_id,
+       ledger.amount,
+       ledger.currency,
+       ledger.metadata,
+       ledger.created_at,
+       ledger.effective_at,
+       ledger.running_balance,
+       transactions.description,
+       counterparty.id AS counterparty_id,
+       counterparty.name AS counterparty_name
+FROM ledger
+JOIN transactions ON transactions.id = ledger.transaction_id
+LEFT JOIN LATERAL (
+    SELECT accounts.id, accounts.name
+    FROM entries other
+    JOIN accounts ON accounts.id = other.account_id
+    WHERE other.transaction_id = ledger.transaction_id
+      AND other.account_id <> sqlc.arg(account_id)
+      AND SIGN(other.amount) <> SIGN(ledger.amount)
+    ORDER BY ABS(other.amount) DESC, other.id
+    LIMIT 1
+) counterparty ON TRUE
+WHERE (sqlc.narg(min_amount)::BIGINT IS NULL OR ABS(ledger.amount) >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::BIGINT IS NULL OR ABS(ledger.amount) <= sqlc.narg(max_amount))
+ORDER BY ledger.created_at DESC, ledger.id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: GetTransaction :one
+SELECT * FROM transactions WHERE id = $1;
+
+-- name: SearchTransactions :many
+SELECT * FROM transactions
+WHERE (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(external_id)::TEXT IS NULL OR external_id = sqlc.narg(external_id))
+  AND (sqlc.narg(created_by)::TEXT IS NULL OR created_by = sqlc.narg(created_by))
+  AND (sqlc.narg(from_date)::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg(from_date))
+  AND (sqlc.narg(to_date)::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg(to_date))
+  AND (sqlc.narg(account_id)::UUID IS NULL OR EXISTS (
+        SELECT 1 FROM entries
+        WHERE entries.transaction_id = transactions.id
+          AND entries.account_id = sqlc.narg(account_id)))
+  AND (sqlc.narg(metadata)::JSONB IS NULL OR metadata @> sqlc.narg(metadata))
+  AND (sqlc.narg(cursor_created_at)::TIMESTAMPTZ IS NULL
+       OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: CreateHold :one
+INSERT INTO holds (id, transaction_id, account_id, counterparty_id, currency, amount, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds WHERE id = $1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds WHERE id = $1 FOR UPDATE;
+
+-- name: GetHoldByTransactionID :one
+SELECT * FROM holds WHERE transaction_id = $1;
+
+-- name: GetExpiredHoldIDs :many
+SELECT id FROM holds
+WHERE status = 'pending' AND expires_at <= NOW()
+ORDER BY expires_at
+LIMIT $1;
+
+-- name: UpdateHoldStatus :one
+UPDATE holds SET status = $2, captured_amount = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ApplyPendingDelta :exec
+INSERT INTO account_balances (account_id, currency, pending, updated_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (account_id) DO UPDATE
+SET pending = account_balances.pending + EXCLUDED.pending,
+    updated_at = NOW();
+
+-- name: PostPendingTransaction :one
+UPDATE transactions SET status = 'posted'
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: UpdateAccountBalancePolicy :one
+UPDATE accounts SET balance_policy = $2, overdraft_limit = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: LockAccountTree :exec
+SELECT pg_advisory_xact_lock(hashtext('accounts_tree'));
+
+-- name: UpdateAccountParent :one
+UPDATE accounts SET parent_id = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetAccountTree :many
+WITH RECURSIVE tree AS (
+    SELECT accounts.id, 0 AS depth, ARRAY[accounts.id] AS path FROM accounts WHERE accounts.id = $1
+    UNION ALL
+    SELECT accounts.id, tree.depth + 1, tree.path || accounts.id
+    FROM accounts JOIN tree ON accounts.parent_id = tree.id
+    -- Stops at an account already on the path instead of looping forever
+    WHERE accounts.id <> ALL(tree.path)
+)
+SELECT accounts.id,
+       accounts.parent_id,
+       accounts.name,
+       accounts.currency,
+       accounts.account_class,
+       accounts.normal_balance,
+       tree.depth::INT AS depth,
+       COALESCE(account_balances.balance, 0)::BIGINT AS balance
+FROM tree
+JOIN accounts ON accounts.id = tree.id
+LEFT JOIN account_balances ON account_balances.account_id = accounts.id
+ORDER BY tree.depth, accounts.name, accounts.id;
+
+-- name: GetAccountTotals :many
+SELECT accounts.id,
+       accounts.name,
+       accounts.currency,
+       accounts.account_class,
+       accounts.normal_balance,
+       COALESCE(SUM(-entries.amount) FILTER (WHERE entries.amount < 0), 0)::BIGINT AS debits,
+       COALESCE(SUM(entries.amount) FILTER (WHERE entries.amount > 0), 0)::BIGINT AS credits
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id
+    AND (sqlc.narg(from_date)::TIMESTAMPTZ IS NULL OR entries.effective_at > sqlc.narg(from_date))
+    AND entries.effective_at <= sqlc.arg(to_date)
+GROUP BY accounts.id
+ORDER BY accounts.currency, accounts.account_class, accounts.name, accounts.id;
+
+-- name: GetUnbalancedTransactions :many
+SELECT transaction_id, currency, SUM(amount)::BIGINT AS total
+FROM entries
+GROUP BY transaction_id, currency
+HAVING SUM(amount) <> 0
+ORDER BY transaction_id, currency;
+
+-- name: GetEntryCurrencyMismatches :many
+SELECT entries.id AS entry_id,
+       entries.transaction_id,
+       entries.account_id,
+       entries.currency AS entry_currency,
+       accounts.currency AS account_currency
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+WHERE entries.currency <> accounts.currency
+ORDER BY entries.seq;
+
+-- name: GetOrphanTransactions :many
+SELECT id, external_id, status, created_at
+FROM transactions
+WHERE status NOT IN ('pending', 'voided')
+  AND NOT EXISTS (SELECT 1 FROM entries WHERE entries.transaction_id = transactions.id)
+ORDER BY created_at, id;
+
+-- name: GetDuplicateExternalIDs :many
+SELECT external_id::TEXT AS external_id, array_agg(id ORDER BY created_at, id)::UUID[] AS transaction_ids
+FROM transactions
+WHERE external_id IS NOT NULL
+GROUP BY external_id
+HAVING COUNT(*) > 1
+ORDER BY external_id;
+
+-- name: LockHashChain :exec
+SELECT pg_advisory_xact_lock(hashtext('transactions_hash_chain'));
+
+-- name: GetHashChainHead :one
+SELECT chain_seq::BIGINT AS chain_seq, hash
+FROM transactions
+WHERE chain_seq IS NOT NULL
+ORDER BY chain_seq DESC
+LIMIT 1;
+
+-- name: LinkTransaction :exec
+UPDATE transactions
+SET hash = sqlc.arg(hash), prev_hash = sqlc.arg(prev_hash), chain_seq = sqlc.arg(chain_seq)::BIGINT, linked_at = clock_timestamp()
+WHERE id = sqlc.arg(id);
+
+-- name: GetUnchainedTransactionIDs :many
+SELECT id FROM transactions
+WHERE chain_seq IS NULL AND status NOT IN ('pending', 'voided')
+ORDER BY created_at, id
+LIMIT $1;
+
+-- name: CountUnchainedTransactions :one
+SELECT COUNT(*) FROM transactions
+WHERE chain_seq IS NULL AND status NOT IN ('pending', 'voided')
+  AND created_at < sqlc.arg(before)::TIMESTAMPTZ;
+
+-- name: GetHashChain :many
+SELECT * FROM transactions
+WHERE chain_seq > sqlc.arg(after_seq)::BIGINT
+ORDER BY chain_seq
+LIMIT sqlc.arg(page_size);
+
+-- name: GetEntriesByTransactionIDs :many
+SELECT id, transaction_id, account_id, TRIM_SCALE(amount)::TEXT AS amount, currency, metadata, created_at, effective_at
+FROM entries
+WHERE transaction_id = ANY(sqlc.arg(transaction_ids)::UUID[])
+ORDER BY transaction_id, id;
+
+-- name: GetUnattestedDays :many
+WITH last AS (
+    SELECT MAX(day) AS day, MAX(last_chain_seq) AS chain_seq FROM ledger_attestations
+),
+linked AS (
+    -- A link committed after its day was attested goes to the next day
+    SELECT GREATEST((transactions.linked_at AT TIME ZONE 'UTC')::DATE, last.day + 1) AS day
+    FROM transactions, last
+    WHERE transactions.chain_seq > COALESCE(last.chain_seq, 0)
+      AND transactions.linked_at < sqlc.arg(cutoff)
+)
+SELECT DISTINCT day::DATE AS day
+FROM linked
+WHERE day < (sqlc.arg(cutoff)::TIMESTAMPTZ AT TIME ZONE 'UTC')::DATE
+ORDER BY day;
+
+-- name: GetLastAttestedChainSeq :one
+SELECT COALESCE(MAX(last_chain_seq), 0)::BIGINT AS chain_seq FROM ledger_attestations;
+
+-- name: GetLastChainSeqBefore :one
+SELECT COALESCE(MAX(chain_seq), 0)::BIGINT AS chain_seq
+FROM transactions
+WHERE chain_seq > sqlc.arg(after_seq)::BIGINT AND linked_at < sqlc.arg(before_time);
+
+-- name: GetLinkedTransactions :many
+SELECT * FROM transactions
+WHERE chain_seq >= sqlc.arg(from_seq)::BIGINT AND chain_seq <= sqlc.arg(to_seq)::BIGINT
+ORDER BY chain_seq;
+
+-- name: CreateAttestation :exec
+INSERT INTO ledger_attestations (day, root, leaf_count, first_chain_seq, last_chain_seq)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (day) DO NOTHING;
+
+-- name: GetAttestation :one
+SELECT * FROM ledger_attestations WHERE day = $1;
+
+-- name: GetAttestationByChainSeq :one
+SELECT * FROM ledger_attestations
+WHERE first_chain_seq <= sqlc.arg(chain_seq)::BIGINT AND last_chain_seq >= sqlc.arg(chain_seq)::BIGINT;
+
+-- name: CreateFxQuote :one
+INSERT INTO fx_quotes (id, source_currency, target_currency, source_amount, target_amount, mid_rate, spread_bps, rate, rate_source, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetFxQuote :one
+SELECT * FROM fx_quotes WHERE id = $1;
+
+-- name: UseFxQuote :one
+UPDATE fx_quotes SET transaction_id = $2, used_at = NOW()
+WHERE id = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING *;
+
+-- name: CreateFxRate :exec
+INSERT INTO fx_rates (id, base, quote, rate, source, as_of)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (base, quote, source, as_of) DO NOTHING;
+
+-- name: GetFxRateAt :one
+SELECT * FROM fx_rates
+WHERE ((base = sqlc.arg(base) AND quote = sqlc.arg(quote)) OR (base = sqlc.arg(quote) AND quote = sqlc.arg(base)))
+  AND as_of <= sqlc.arg(at)
+ORDER BY as_of DESC, created_at DESC
+LIMIT 1;
+
+-- name: GetFxRateHistory :many
+SELECT * FROM fx_rates
+WHERE ((base = sqlc.arg(base) AND quote = sqlc.arg(quote)) OR (base = sqlc.arg(quote) AND quote = sqlc.arg(base)))
+  AND as_of <= sqlc.arg(at)
+ORDER BY as_of, created_at;
+
+-- name: GetForeignDailyFlows :many
+SELECT accounts.id AS account_id,
+       accounts.name,
+       accounts.currency,
+       accounts.account_class,
+       accounts.normal_balance,
+       (entries.effective_at AT TIME ZONE 'UTC')::DATE AS day,
+       SUM(entries.amount)::BIGINT AS amount
+FROM accounts
+JOIN entries ON entries.account_id = accounts.id
+WHERE accounts.currency <> sqlc.arg(reporting_currency)
+  AND accounts.account_class IN ('asset', 'liability')
+  AND entries.effective_at <= sqlc.arg(as_of)
+GROUP BY accounts.id, day
+ORDER BY accounts.currency, accounts.account_class, accounts.name, accounts.id, day;
+
+-- name: GetActiveFxSpread :one
+SELECT * FROM fx_spreads
+WHERE active AND base = $1 AND quote = $2;
@@ -1,4 +1,5 @@
 CREATE TYPE currency AS ENUM ('USD', 'BRL');
+CREATE TYPE account_class AS ENUM ('asset', 'liability', 'equity', 'revenue', 'expense');
 
 -- =========================================
 -- ACCOUNTS
@@ -8,9 +9,20 @@ CREATE TABLE accounts (
     name TEXT NOT NULL,
     currency currency NOT NULL,
     metadata JSONB DEFAULT '{}'::jsonb,
-    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    status TEXT NOT NULL DEFAULT 'open',            -- open | closed
+    closed_at TIMESTAMPTZ,
+    balance_policy TEXT NOT NULL DEFAULT 'non_negative' -- non_negative | overdraft | unlimited
+        CHECK (balance_policy IN ('non_negative', 'overdraft', 'unlimited')),
+    overdraft_limit BIGINT NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0),
+    account_class account_class NOT NULL DEFAULT 'liability',
+    normal_balance TEXT NOT NULL DEFAULT 'credit'  -- debit | credit
+        CHECK (normal_balance IN ('debit', 'credit')),
+    parent_id UUID REFERENCES accounts(id) ON DELETE RESTRICT -- rolls up into
 );
 
+CREATE INDEX accounts_parent_id_idx ON accounts (parent_id);
+
 -- =========================================
 -- TRANSACTIONS (logical grouping)
 -- =========================================
@@ -18,11 +30,25 @@ CREATE TABLE transactions (
     id UUID PRIMARY KEY,
     external_id TEXT UNIQUE,                       -- idempotency key
     description TEXT,
-    status TEXT NOT NULL DEFAULT 'posted',         -- posted | pending | reversed
+    status TEXT NOT NULL DEFAULT 'posted',         -- posted | pending | partially_reversed | reversed | voided
     created_by TEXT,
-    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reverses_transaction_id UUID REFERENCES transactions(id) ON DELETE RESTRICT,
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    hash BYTEA,                                    -- sha256(prev_hash || canonical content)
+    prev_hash BYTEA,                               -- hash of the previous link, NULL for the first
+    chain_seq BIGINT,                              -- position in the hash chain
+    linked_at TIMESTAMPTZ                          -- when it joined the chain, picks its attestation day
 );
 
+CREATE INDEX transactions_linked_at_idx ON transactions (linked_at);
+
+CREATE UNIQUE INDEX transactions_chain_seq_idx ON transactions (chain_seq);
+CREATE INDEX transactions_unchained_idx ON transactions (created_at, id) WHERE chain_seq IS NULL;
+CREATE INDEX transactions_created_at_idx ON transactions (created_at, id);
+CREATE INDEX transactions_created_by_idx ON transactions (created_by);
+CREATE INDEX transactions_metadata_idx ON transactions USING GIN (metadata jsonb_path_ops);
+
 -- =========================================
 -- ENTRIES (individual money movements)
 -- =========================================
@@ -33,5 +59,141 @@ CREATE TABLE entries (
     amount NUMERIC(20,4) NOT NULL,                 -- positive or negative
     currency currency NOT NULL,
     metadata JSONB DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    effective_at TIMESTAMPTZ NOT NULL DEFAULT NOW(), -- when the entry counts for balances
+    seq BIGSERIAL                                  -- insertion order
+);
+
+CREATE INDEX entries_account_effective_at_idx ON entries (account_id, effective_at);
+CREATE UNIQUE INDEX entries_account_seq_idx ON entries (account_id, seq);
+CREATE INDEX entries_account_created_at_idx ON entries (account_id, created_at, id);
+CREATE INDEX entries_transaction_id_idx ON entries (transaction_id);
+CREATE INDEX entries_seq_idx ON entries (seq);
+
+-- =========================================
+-- FEE SCHEDULES (fees charged per transaction type and currency)
+-- =========================================
+CREATE TABLE fee_schedules (
+    id UUID PRIMARY KEY,
+    transaction_type TEXT NOT NULL,                -- transfer | fx_transfer
+    currency currency NOT NULL,
+    kind TEXT NOT NULL,                            -- percentage | flat | tiered
+    bps BIGINT NOT NULL DEFAULT 0,                 -- basis points for percentage fees
+    flat_amount BIGINT NOT NULL DEFAULT 0,         -- minor units for flat fees
+    min_amount BIGINT,                             -- optional floor
+    max_amount BIGINT,                             -- optional cap
+    tiers JSONB NOT NULL DEFAULT '[]'::jsonb,      -- [{"up_to": 100000, "bps": 150, "flat": 0}, ...]
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- =========================================
+-- ACCOUNT BALANCES (materialized, updated with every entry)
+-- =========================================
+CREATE TABLE account_balances (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE RESTRICT,
+    currency currency NOT NULL,
+    balance BIGINT NOT NULL DEFAULT 0,
+    version BIGINT NOT NULL DEFAULT 0,             -- number of entries applied
+    last_entry_id UUID REFERENCES entries(id) ON DELETE RESTRICT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    pending BIGINT NOT NULL DEFAULT 0              -- held, not yet posted
+);
+
+-- =========================================
+-- BALANCE SNAPSHOTS (sum of an account's entries up to a seq)
+-- =========================================
+CREATE TABLE balance_snapshots (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    currency currency NOT NULL,
+    balance BIGINT NOT NULL,
+    up_to_seq BIGINT NOT NULL,
+    taken_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, up_to_seq)
+);
+
+CREATE INDEX balance_snapshots_account_taken_at_idx ON balance_snapshots (account_id, taken_at);
+
+-- =========================================
+-- HOLDS (funds reserved by a pending transaction)
+-- =========================================
+CREATE TABLE holds (
+    id UUID PRIMARY KEY,
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE RESTRICT,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    counterparty_id UUID NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    currency currency NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    captured_amount BIGINT NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',         -- pending | captured | voided | expired
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX holds_pending_expires_at_idx ON holds (expires_at) WHERE status = 'pending';
+
+-- =========================================
+-- FX QUOTES (a rate locked for one conversion until it expires)
+-- =========================================
+CREATE TABLE fx_quotes (
+    id UUID PRIMARY KEY,
+    source_currency currency NOT NULL,
+    target_currency currency NOT NULL,
+    source_amount BIGINT NOT NULL CHECK (source_amount > 0),
+    target_amount BIGINT NOT NULL CHECK (target_amount > 0),
+    mid_rate NUMERIC(38,18) NOT NULL,               -- provider rate before the spread
+    spread_bps INTEGER NOT NULL DEFAULT 0,
+    rate NUMERIC(38,18) NOT NULL,                   -- rate shown and booked
+    rate_source TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    transaction_id UUID UNIQUE REFERENCES transactions(id) ON DELETE RESTRICT,
+    used_at TIMESTAMPTZ,                            -- set once, by the transfer that books it
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (source_currency <> target_currency)
+);
+
+-- =========================================
+-- FX RATES (every rate the providers quoted, kept for historical lookups)
+-- =========================================
+CREATE TABLE fx_rates (
+    id UUID PRIMARY KEY,
+    base currency NOT NULL,
+    quote currency NOT NULL,
+    rate NUMERIC(38,18) NOT NULL CHECK (rate > 0),  -- price of one base in quote
+    source TEXT NOT NULL,
+    as_of TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX fx_rates_pair_source_as_of_idx ON fx_rates (base, quote, source, as_of);
+CREATE INDEX fx_rates_pair_as_of_idx ON fx_rates (base, quote, as_of);
+
+-- =========================================
+-- FX SPREADS (margin taken off the mid rate per currency pair)
+-- =========================================
+CREATE TABLE fx_spreads (
+    id UUID PRIMARY KEY,
+    base currency NOT NULL,                         -- converted from
+    quote currency NOT NULL,                        -- converted to
+    bps INTEGER NOT NULL DEFAULT 0                  -- basis points off the mid rate
+        CHECK (bps >= 0 AND bps < 10000),
+    tiers JSONB NOT NULL DEFAULT '[]'::jsonb,       -- [{"up_to": 100000, "bps": 100}, {"up_to": null, "bps": 50}]
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (base <> quote)
+);
+
+CREATE UNIQUE INDEX fx_spreads_active_pair_idx ON fx_spreads (base, quote) WHERE active;
+
+-- =========================================
+-- LEDGER ATTESTATIONS (daily Merkle roots over the linked transactions)
+-- =========================================
+CREATE TABLE ledger_attestations (
+    day DATE PRIMARY KEY,                           -- UTC day of linked_at
+    root BYTEA NOT NULL,
+    leaf_count BIGINT NOT NULL,
+    first_chain_seq BIGINT NOT NULL,
+    last_chain_seq BIGINT NOT NULL,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
@@ -0,0 +1,50 @@
+package domain
+
+import "github.com/google/uuid"
+
+const (
+	AccountStatusOpen   = "open"
+	AccountStatusClosed = "closed"
+)
+
+// Balance policies decide how far a posting can take an account balance
+const (
+	BalancePolicyNonNegative = "non_negative"
+	BalancePolicyOverdraft   = "overdraft"
+	BalancePolicyUnlimited   = "unlimited"
+)
+
+type NewAccount struct {
+	Name     string
+	Currency string
+	Metadata []byte
+	// BalancePolicy defaults to non_negative, OverdraftLimit only applies
+	// to the overdraft policy
+	BalancePolicy  string
+	OverdraftLimit int64
+	// Class defaults to liability, the class of customer wallets. ParentID
+	// is the account this one rolls up into, if any
+	Class    string
+	ParentID uuid.UUID
+}
+
+// Sides an account class normally carries its balance on. Entries follow the
+// ledger sign convention, debits negative and credits positive.
+const (
+	NormalBalanceDebit  = "debit"
+	NormalBalanceCredit = "credit"
+)
+
+// AccountNode is an account in the chart of accounts with its own balance
+// and the total rolled up from it and every account below it.
+type AccountNode struct {
+	ID            uuid.UUID
+	ParentID      uuid.UUID
+	Name          string
+	Currency      string
+	Class         string
+	NormalBalance string
+	Balance       int64
+	Total         int64
+	Children      []*AccountNode
+}
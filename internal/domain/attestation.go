@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/merkle"
+	"github.com/google/uuid"
+)
+
+// Attestation is the Merkle root over the transactions linked into the hash
+// chain during one UTC day, in chain order.
+type Attestation struct {
+	Day           time.Time
+	Root          []byte
+	LeafCount     int64
+	FirstChainSeq int64
+	LastChainSeq  int64
+	CreatedAt     time.Time
+}
+
+// InclusionProof ties one transaction to the root attested for its day.
+// LeafData is the canonical content of the transaction, hashing it with
+// merkle.LeafHash gives Leaf, and folding Path over Leaf gives Root.
+type InclusionProof struct {
+	TransactionID uuid.UUID
+	Day           time.Time
+	Root          []byte
+	LeafCount     int64
+	Index         int
+	LeafData      []byte
+	Leaf          []byte
+	Path          []merkle.Step
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BalanceDrift reports an account whose materialized balance disagrees with
+// the sum of its entries.
+type BalanceDrift struct {
+	AccountID    uuid.UUID
+	Currency     string
+	Materialized int64
+	Computed     int64
+}
+
+// AccountBalance is the materialized position of an account. Pending is the
+// amount held and not yet posted, Available is what can still be spent.
+// Historical balances carry the instant they were computed for in AsOf.
+type AccountBalance struct {
+	AccountID uuid.UUID
+	Currency  string
+	Posted    int64
+	Pending   int64
+	Available int64
+	AsOf      time.Time
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Reasons a link of the hash chain fails verification
+const (
+	BrokenLinkMissing  = "missing_link"       // a chain_seq was skipped, a transaction was deleted
+	BrokenLinkPrevHash = "prev_hash_mismatch" // the link does not point at the hash before it
+	BrokenLinkContent  = "content_mismatch"   // the transaction or its entries were edited
+)
+
+// ChainVerification is the result of walking the hash chain from its first
+// link. Valid only depends on Broken. Unchained counts settled transactions
+// still not linked well after the background appender should have reached
+// them, meaning it is stalled or rows were written around the service.
+type ChainVerification struct {
+	Valid     bool
+	Checked   int64
+	HeadSeq   int64
+	HeadHash  []byte
+	Unchained int64
+	Broken    *BrokenLink
+}
+
+// BrokenLink is the first link that failed verification
+type BrokenLink struct {
+	ChainSeq      int64
+	TransactionID uuid.UUID
+	Reason        string
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HoldStatusPending  = "pending"
+	HoldStatusCaptured = "captured"
+	HoldStatusVoided   = "voided"
+	HoldStatusExpired  = "expired"
+)
+
+// NewHold reserves Amount on AccountID in favour of CounterpartyID. A zero
+// TTL falls back to the configured default.
+type NewHold struct {
+	IdempotencyKey uuid.UUID
+	AccountID      uuid.UUID
+	CounterpartyID uuid.UUID
+	Currency       string
+	Amount         int64
+	Description    string
+	CreatedBy      string
+	Metadata       []byte
+	TTL            time.Duration
+}
+
+// Hold is an amount reserved on an account by a pending transaction, until it
+// is captured, voided or expires.
+type Hold struct {
+	ID             uuid.UUID
+	TransactionID  uuid.UUID
+	AccountID      uuid.UUID
+	CounterpartyID uuid.UUID
+	Currency       string
+	Amount         int64
+	CapturedAmount int64
+	Status         string
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+	// Replayed is set when the hold was created by an earlier request
+	// carrying the same idempotency key.
+	Replayed bool
+}
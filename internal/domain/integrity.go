@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IntegrityReport lists every violation of the ledger invariants found in a
+// single consistent read of the database.
+type IntegrityReport struct {
+	CheckedAt              time.Time
+	UnbalancedTransactions []UnbalancedTransaction
+	CurrencyMismatches     []CurrencyMismatch
+	OrphanTransactions     []OrphanTransaction
+	BalanceDrifts          []BalanceDrift
+	SnapshotDrifts         []SnapshotDrift
+	DuplicateExternalIDs   []DuplicateExternalID
+}
+
+// Violations counts the problems found across every check
+func (r IntegrityReport) Violations() int {
+	return len(r.UnbalancedTransactions) +
+		len(r.CurrencyMismatches) +
+		len(r.OrphanTransactions) +
+		len(r.BalanceDrifts) +
+		len(r.SnapshotDrifts) +
+		len(r.DuplicateExternalIDs)
+}
+
+// UnbalancedTransaction is a transaction whose entries in a currency do not
+// sum to zero. Total is the amount left over.
+type UnbalancedTransaction struct {
+	TransactionID uuid.UUID
+	Currency      string
+	Total         int64
+}
+
+// CurrencyMismatch is an entry booked in a currency other than its account's
+type CurrencyMismatch struct {
+	EntryID         uuid.UUID
+	TransactionID   uuid.UUID
+	AccountID       uuid.UUID
+	EntryCurrency   string
+	AccountCurrency string
+}
+
+// OrphanTransaction is a settled transaction header without any entries.
+// Pending and voided headers legitimately have none.
+type OrphanTransaction struct {
+	TransactionID uuid.UUID
+	ExternalID    string
+	Status        string
+	CreatedAt     time.Time
+}
+
+// SnapshotDrift is an account whose latest balance snapshot differs from the
+// sum of its entries up to the snapshot's seq. Later snapshots chain from it,
+// so as of balances stay off until it is fixed.
+type SnapshotDrift struct {
+	AccountID uuid.UUID
+	Currency  string
+	UpToSeq   int64
+	Snapshot  int64
+	Computed  int64
+}
+
+// DuplicateExternalID is an idempotency key shared by several transactions
+type DuplicateExternalID struct {
+	ExternalID     string
+	TransactionIDs []uuid.UUID
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/google/uuid"
+)
+
+// NewQuote asks for the price of converting Amount of SourceCurrency into
+// TargetCurrency.
+type NewQuote struct {
+	SourceCurrency string
+	TargetCurrency string
+	Amount         int64
+}
+
+// Quote locks a conversion rate for a short while. Rate is MidRate after the
+// spread, it is what the user is shown and what the transfer booking the
+// quote applies. A quote books a single transfer, TransactionID is set once
+// it has been used.
+type Quote struct {
+	ID             uuid.UUID
+	SourceCurrency string
+	TargetCurrency string
+	SourceAmount   int64
+	TargetAmount   int64
+	MidRate        fx.Rate
+	SpreadBps      int32
+	Rate           fx.Rate
+	ExpiresAt      time.Time
+	TransactionID  uuid.UUID
+	UsedAt         time.Time
+	CreatedAt      time.Time
+}
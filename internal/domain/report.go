@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/fx"
+	"github.com/google/uuid"
+)
+
+// AccountTotals is what an account was debited and credited over a period.
+// Balance is the difference read on the account's normal side, so a positive
+// balance is the usual one for its class.
+type AccountTotals struct {
+	AccountID     uuid.UUID
+	Name          string
+	Currency      string
+	Class         string
+	NormalBalance string
+	Debits        int64
+	Credits       int64
+	Balance       int64
+}
+
+// TrialBalance lists the debit and credit totals of every account with
+// activity up to AsOf. The books balance when the debits equal the credits
+// in every currency.
+type TrialBalance struct {
+	AsOf       time.Time
+	Currencies []TrialBalanceCurrency
+	Balanced   bool
+}
+
+// TrialBalanceCurrency holds the accounts and totals of a single currency.
+type TrialBalanceCurrency struct {
+	Currency string
+	Accounts []AccountTotals
+	Debits   int64
+	Credits  int64
+	Balanced bool
+}
+
+// ReportSection groups the accounts of one class with their summed balance.
+type ReportSection struct {
+	Class    string
+	Accounts []AccountTotals
+	Total    int64
+}
+
+// BalanceSheet is the position of the books at AsOf. Revenue and expenses
+// are not closed into equity, so their difference is carried as NetIncome.
+type BalanceSheet struct {
+	AsOf       time.Time
+	Currencies []BalanceSheetCurrency
+	Balanced   bool
+}
+
+// BalanceSheetCurrency is balanced when assets equal liabilities plus
+// equity plus net income.
+type BalanceSheetCurrency struct {
+	Currency    string
+	Assets      ReportSection
+	Liabilities ReportSection
+	Equity      ReportSection
+	NetIncome   int64
+	Balanced    bool
+}
+
+// IncomeStatement is what the books earned and spent between From
+// (exclusive) and To (inclusive).
+type IncomeStatement struct {
+	From       time.Time
+	To         time.Time
+	Currencies []IncomeStatementCurrency
+}
+
+// IncomeStatementCurrency holds the revenue and expenses of one currency.
+type IncomeStatementCurrency struct {
+	Currency  string
+	Revenue   ReportSection
+	Expenses  ReportSection
+	NetIncome int64
+}
+
+// Revaluation values the foreign currency assets and liabilities in
+// Currency at AsOf. Rates holds the closing rate of every foreign currency.
+type Revaluation struct {
+	AsOf           time.Time
+	Currency       string
+	Rates          []fx.Rate
+	Accounts       []RevaluationLine
+	UnrealizedGain int64
+}
+
+// RevaluationLine is one foreign account. Balance is in the account currency
+// and read on its normal side, the values are in the reporting currency:
+// CarryingValue converts every movement at the rate of its day and
+// RevaluedValue converts the balance at the closing rate. UnrealizedGain is
+// positive when the rates moved in favour of the books.
+type RevaluationLine struct {
+	AccountID      uuid.UUID
+	Name           string
+	Currency       string
+	Class          string
+	NormalBalance  string
+	Balance        int64
+	CarryingValue  int64
+	RevaluedValue  int64
+	UnrealizedGain int64
+}
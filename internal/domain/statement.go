@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatementFilter narrows an account statement. Zero values mean no bound,
+// amounts are compared by magnitude and Cursor continues a previous page.
+type StatementFilter struct {
+	From      time.Time
+	To        time.Time
+	MinAmount *int64
+	MaxAmount *int64
+	Cursor    string
+	Limit     int
+}
+
+// StatementLine is one entry of an account statement with the account
+// balance right after it.
+type StatementLine struct {
+	EntryID          uuid.UUID
+	TransactionID    uuid.UUID
+	Amount           int64
+	Currency         string
+	Metadata         []byte
+	CreatedAt        time.Time
+	EffectiveAt      time.Time
+	RunningBalance   int64
+	Description      string
+	CounterpartyID   uuid.UUID
+	CounterpartyName string
+}
+
+// Statement is a page of statement lines, newest first. NextCursor is empty
+// on the last page.
+type Statement struct {
+	Lines      []StatementLine
+	NextCursor string
+}
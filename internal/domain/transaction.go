@@ -1,6 +1,18 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatusPosted            = "posted"
+	StatusPending           = "pending"
+	StatusPartiallyReversed = "partially_reversed"
+	StatusReversed          = "reversed"
+	StatusVoided            = "voided"
+)
 
 type Transaction struct {
 	From          uuid.UUID
@@ -8,4 +20,99 @@ type Transaction struct {
 	Currency      string
 	Value         int64
 	CorrelationId uuid.UUID
+	// QuoteID books a cross-currency transfer at the rate locked by an fx
+	// quote instead of the current one
+	QuoteID uuid.UUID
+}
+
+// Posting is a generic ledger transaction made of any number of legs. The
+// legs must sum to zero per currency.
+type Posting struct {
+	IdempotencyKey uuid.UUID
+	Description    string
+	CreatedBy      string
+	Entries        []EntryRequest
+	// ReversesTransactionID links a reversal to the transaction it undoes
+	ReversesTransactionID uuid.UUID
+	// EffectiveAt is when the entries count for balances, zero meaning the
+	// moment they are recorded. It may be backdated but never in the future
+	EffectiveAt time.Time
+	Metadata    []byte
+	// PendingTransactionID posts the entries onto an existing pending header,
+	// the one opened by a hold, instead of creating a new transaction
+	PendingTransactionID uuid.UUID
+	// QuoteID is the fx quote the posting books, used up in the same
+	// transaction so it can not book a second one
+	QuoteID uuid.UUID
+}
+
+// Reversal undoes a posted transaction. A zero Amount reverses everything
+// that was not reversed yet.
+type Reversal struct {
+	TransactionID  uuid.UUID
+	Amount         int64
+	Reason         string
+	CreatedBy      string
+	IdempotencyKey uuid.UUID
+}
+
+type EntryRequest struct {
+	AccountID uuid.UUID
+	Amount    int64
+	Currency  string
+	Metadata  []byte
+}
+
+type Entry struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	AccountID     uuid.UUID
+	Amount        int64
+	Currency      string
+	Metadata      []byte
+	CreatedAt     time.Time
+	EffectiveAt   time.Time
+}
+
+type TransactionResult struct {
+	ID                    uuid.UUID
+	ExternalID            string
+	Description           string
+	CreatedBy             string
+	ReversesTransactionID uuid.UUID
+	Status                string
+	Metadata              []byte
+	CreatedAt             time.Time
+	Entries               []Entry
+	// Hash links the transaction into the tamper evident chain, covering its
+	// content and the hash of the link before it. The chain is appended in
+	// the background, so they are empty until the transaction is linked.
+	Hash     []byte
+	PrevHash []byte
+	ChainSeq int64
+	// Replayed is set when the result belongs to a transaction posted by an
+	// earlier request carrying the same idempotency key.
+	Replayed bool
+}
+
+// TransactionFilter narrows a transaction search. Empty fields are ignored,
+// Metadata is matched by JSONB containment and Cursor continues a previous
+// page.
+type TransactionFilter struct {
+	Status     string
+	ExternalID string
+	CreatedBy  string
+	From       time.Time
+	To         time.Time
+	AccountID  uuid.UUID
+	Metadata   []byte
+	Cursor     string
+	Limit      int
+}
+
+// TransactionPage is a page of transaction headers, newest first. NextCursor
+// is empty on the last page.
+type TransactionPage struct {
+	Transactions []TransactionResult
+	NextCursor   string
 }
@@ -0,0 +1,72 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CachedProvider keeps the rates quoted by another provider in Redis for a
+// while, so a burst of conversions does not hit a paid vendor every time.
+// Cache failures are logged and fall through to the provider.
+type CachedProvider struct {
+	next  RateProvider
+	redis *redis.Client
+	ttl   time.Duration
+}
+
+type cachedRate struct {
+	Value  string    `json:"value"`
+	Source string    `json:"source"`
+	AsOf   time.Time `json:"as_of"`
+}
+
+func NewCachedProvider(next RateProvider, redis *redis.Client, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{next: next, redis: redis, ttl: ttl}
+}
+
+func (c *CachedProvider) Rate(ctx context.Context, pair Pair) (Rate, error) {
+	key := "fx:rate:" + pair.String()
+
+	val, err := c.redis.Get(ctx, key).Result()
+	if err == nil {
+		if rate, err := decodeCachedRate(pair, val); err == nil {
+			return rate, nil
+		}
+	} else if err != redis.Nil {
+		slog.Error("error getting rate from cache", slog.String("error", err.Error()))
+	}
+
+	rate, err := c.next.Rate(ctx, pair)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	data, err := json.Marshal(cachedRate{Value: rate.Value.RatString(), Source: rate.Source, AsOf: rate.AsOf})
+	if err == nil {
+		if err := c.redis.Set(ctx, key, data, c.ttl).Err(); err != nil {
+			slog.Error("error setting rate to cache", slog.String("error", err.Error()))
+		}
+	}
+
+	return rate, nil
+}
+
+func decodeCachedRate(pair Pair, val string) (Rate, error) {
+	var cached cachedRate
+	if err := json.Unmarshal([]byte(val), &cached); err != nil {
+		return Rate{}, err
+	}
+
+	value, ok := new(big.Rat).SetString(cached.Value)
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: cached %q", ErrInvalidRate, cached.Value)
+	}
+
+	return Rate{Pair: pair, Value: value, Source: cached.Source, AsOf: cached.AsOf}, nil
+}
@@ -0,0 +1,33 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Chain asks its providers in order and returns the first rate quoted. It
+// only fails when every provider does, with all of their errors.
+type Chain struct {
+	providers []RateProvider
+}
+
+func NewChain(providers ...RateProvider) *Chain {
+	return &Chain{providers: providers}
+}
+
+func (c *Chain) Rate(ctx context.Context, pair Pair) (Rate, error) {
+	errs := make([]error, 0, len(c.providers))
+	for _, provider := range c.providers {
+		rate, err := provider.Rate(ctx, pair)
+		if err == nil {
+			return rate, nil
+		}
+		if ctx.Err() != nil {
+			return Rate{}, ctx.Err()
+		}
+		errs = append(errs, err)
+	}
+
+	return Rate{}, fmt.Errorf("%w: %s: %w", ErrRateUnavailable, pair, errors.Join(errs...))
+}
@@ -0,0 +1,39 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type failingProvider struct {
+	calls int
+}
+
+func (f *failingProvider) Rate(context.Context, Pair) (Rate, error) {
+	f.calls++
+	return Rate{}, errors.New("vendor down")
+}
+
+func TestChain(t *testing.T) {
+	pair := Pair{Base: "USD", Quote: "BRL"}
+	static, _ := NewStaticProvider(map[string]string{"USDBRL": "5.33"})
+	down := &failingProvider{}
+
+	rate, err := NewChain(down, static).Rate(context.Background(), pair)
+	if err != nil {
+		t.Fatalf("Expected the static provider to answer, got %v", err)
+	}
+	if rate.Source != "static" || down.calls != 1 {
+		t.Errorf("Expected one failed call before the static rate, got %d calls and source %s", down.calls, rate.Source)
+	}
+
+	if _, err := NewChain(static, down).Rate(context.Background(), pair); err != nil || down.calls != 1 {
+		t.Errorf("Expected the chain to stop at the first rate, got %v and %d calls", err, down.calls)
+	}
+
+	_, err = NewChain(down, down).Rate(context.Background(), pair)
+	if !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("Expected ErrRateUnavailable when every provider fails, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+// Package fx provides exchange rates behind a single RateProvider interface,
+// so the ledger can switch rate vendors, fall back between them or run
+// offline on fixed rates without changing how conversions are posted.
+//
+// Rates are exact decimals held in a big.Rat and quote how many units of the
+// quote currency one unit of the base currency buys.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// maxDecimals bounds how many places a rate is printed with when it has no
+// exact decimal form
+const maxDecimals = 18
+
+var (
+	ErrRateUnavailable error = errors.New("no rate available for the currency pair")
+	ErrInvalidPair     error = errors.New("invalid currency pair")
+	ErrInvalidRate     error = errors.New("invalid rate")
+)
+
+// RateProvider returns the current rate of a currency pair, or an error
+// wrapping ErrRateUnavailable when it has none.
+type RateProvider interface {
+	Rate(ctx context.Context, pair Pair) (Rate, error)
+}
+
+// Pair is a base and quote currency, written as "USDBRL"
+type Pair struct {
+	Base  string
+	Quote string
+}
+
+// ParsePair reads a pair written as "USDBRL" or "USD/BRL"
+func ParsePair(s string) (Pair, error) {
+	s = strings.ToUpper(strings.ReplaceAll(s, "/", ""))
+	if len(s) != 6 {
+		return Pair{}, fmt.Errorf("%w: %q", ErrInvalidPair, s)
+	}
+	return Pair{Base: s[:3], Quote: s[3:]}, nil
+}
+
+func (p Pair) String() string {
+	return p.Base + p.Quote
+}
+
+// Inverse swaps base and quote
+func (p Pair) Inverse() Pair {
+	return Pair{Base: p.Quote, Quote: p.Base}
+}
+
+// Rate is the price of one unit of Pair.Base in Pair.Quote. Source names the
+// provider that quoted it.
+type Rate struct {
+	Pair   Pair
+	Value  *big.Rat
+	Source string
+	AsOf   time.Time
+}
+
+// ParseRate reads a positive decimal such as "5.33"
+func ParseRate(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return r, nil
+}
+
+// Inverse returns the rate of the inverse pair
+func (r Rate) Inverse() Rate {
+	return Rate{
+		Pair:   r.Pair.Inverse(),
+		Value:  new(big.Rat).Inv(r.Value),
+		Source: r.Source,
+		AsOf:   r.AsOf,
+	}
+}
+
+// Decimal prints the rate exactly when it has a finite decimal form, and
+// rounded to maxDecimals places otherwise.
+func (r Rate) Decimal() string {
+	if r.Value.IsInt() {
+		return r.Value.Num().String()
+	}
+
+	for places := 1; places <= maxDecimals; places++ {
+		s := r.Value.FloatString(places)
+		if exact, _ := new(big.Rat).SetString(s); exact.Cmp(r.Value) == 0 {
+			return s
+		}
+	}
+	return strings.TrimRight(r.Value.FloatString(maxDecimals), "0")
+}
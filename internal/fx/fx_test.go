@@ -0,0 +1,63 @@
+package fx
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestParsePair(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Pair
+	}{
+		{in: "USDBRL", want: Pair{Base: "USD", Quote: "BRL"}},
+		{in: "usd/brl", want: Pair{Base: "USD", Quote: "BRL"}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePair(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParsePair(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	if _, err := ParsePair("USDBR"); !errors.Is(err, ErrInvalidPair) {
+		t.Errorf("Expected ErrInvalidPair for a short pair, got %v", err)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	for _, in := range []string{"abc", "0", "-5.33", ""} {
+		if _, err := ParseRate(in); !errors.Is(err, ErrInvalidRate) {
+			t.Errorf("Expected ErrInvalidRate for %q, got %v", in, err)
+		}
+	}
+}
+
+func TestRateDecimal(t *testing.T) {
+	tests := []struct {
+		rate *big.Rat
+		want string
+	}{
+		{rate: big.NewRat(533, 100), want: "5.33"},
+		{rate: big.NewRat(2, 1), want: "2"},
+		{rate: big.NewRat(1, 8), want: "0.125"},
+		{rate: big.NewRat(1, 3), want: "0.333333333333333333"},
+	}
+	for _, tt := range tests {
+		if got := (Rate{Value: tt.rate}).Decimal(); got != tt.want {
+			t.Errorf("Decimal of %s = %s, want %s", tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestRateInverse(t *testing.T) {
+	rate := Rate{Pair: Pair{Base: "USD", Quote: "BRL"}, Value: big.NewRat(5, 1)}
+	inverse := rate.Inverse()
+	if inverse.Pair != (Pair{Base: "BRL", Quote: "USD"}) || inverse.Value.Cmp(big.NewRat(1, 5)) != 0 {
+		t.Errorf("Expected BRLUSD at 1/5, got %s at %s", inverse.Pair, inverse.Value)
+	}
+	if rate.Value.Cmp(big.NewRat(5, 1)) != 0 {
+		t.Error("Expected Inverse to leave the original rate alone")
+	}
+}
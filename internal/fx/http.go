@@ -0,0 +1,57 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httpclient"
+)
+
+// HTTPProvider quotes rates from an exchangerate-api shaped endpoint:
+// GET <baseURL><BASE> answers {"conversion_rates": {"BRL": 5.33, ...}}.
+type HTTPProvider struct {
+	client  *httpclient.Client
+	baseURL string
+}
+
+type ratesResponse struct {
+	ConversionRates map[string]json.Number `json:"conversion_rates"`
+}
+
+func NewHTTPProvider(client *httpclient.Client, baseURL string) *HTTPProvider {
+	return &HTTPProvider{client: client, baseURL: baseURL}
+}
+
+func (h *HTTPProvider) Rate(ctx context.Context, pair Pair) (Rate, error) {
+	response, err := h.client.Get(ctx, h.baseURL+pair.Base, nil, nil)
+	if err != nil {
+		return Rate{}, fmt.Errorf("%w: %s: %v", ErrRateUnavailable, pair, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return Rate{}, fmt.Errorf("%w: %s: rate api answered %d", ErrRateUnavailable, pair, response.StatusCode)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	decoder.UseNumber()
+
+	var rates ratesResponse
+	if err := decoder.Decode(&rates); err != nil {
+		return Rate{}, fmt.Errorf("%w: %s: error decoding rates: %v", ErrRateUnavailable, pair, err)
+	}
+
+	quoted, ok := rates.ConversionRates[pair.Quote]
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: %s", ErrRateUnavailable, pair)
+	}
+	value, err := ParseRate(quoted.String())
+	if err != nil {
+		return Rate{}, fmt.Errorf("%w: %s: %v", ErrRateUnavailable, pair, err)
+	}
+
+	return Rate{Pair: pair, Value: value, Source: "http", AsOf: time.Now()}, nil
+}
@@ -0,0 +1,40 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IgorGrieder/Small-Ledger/internal/http/httpclient"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/latest/USD" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"conversion_rates": {"USD": 1, "BRL": 5.3312}}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(httpclient.NewClient(5*time.Second, 5, 5*time.Second), server.URL+"/latest/")
+
+	rate, err := provider.Rate(context.Background(), Pair{Base: "USD", Quote: "BRL"})
+	if err != nil {
+		t.Fatalf("Expected a rate, got %v", err)
+	}
+	if rate.Decimal() != "5.3312" || rate.Source != "http" {
+		t.Errorf("Expected 5.3312 from http, got %s from %s", rate.Decimal(), rate.Source)
+	}
+
+	if _, err := provider.Rate(context.Background(), Pair{Base: "USD", Quote: "EUR"}); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("Expected ErrRateUnavailable for a missing quote, got %v", err)
+	}
+	if _, err := provider.Rate(context.Background(), Pair{Base: "BRL", Quote: "USD"}); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("Expected ErrRateUnavailable for a failed request, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package fx
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// RateRecorder keeps the rates a provider quoted, so they outlive any cache
+type RateRecorder interface {
+	RecordRate(ctx context.Context, rate Rate) error
+}
+
+// RecordingProvider hands every rate another provider quotes to a recorder.
+// A rate already recorded, same pair, source and time, is not recorded again.
+// Recording failures are logged and the rate is returned anyway.
+type RecordingProvider struct {
+	next     RateProvider
+	recorder RateRecorder
+
+	mu       sync.Mutex
+	recorded map[string]time.Time
+}
+
+func NewRecordingProvider(next RateProvider, recorder RateRecorder) *RecordingProvider {
+	return &RecordingProvider{
+		next:     next,
+		recorder: recorder,
+		recorded: make(map[string]time.Time),
+	}
+}
+
+func (r *RecordingProvider) Rate(ctx context.Context, pair Pair) (Rate, error) {
+	rate, err := r.next.Rate(ctx, pair)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	key := rate.Pair.String() + ":" + rate.Source
+	r.mu.Lock()
+	last, seen := r.recorded[key]
+	r.mu.Unlock()
+	if seen && last.Equal(rate.AsOf) {
+		return rate, nil
+	}
+
+	if err := r.recorder.RecordRate(ctx, rate); err != nil {
+		slog.Error("error recording rate",
+			slog.String("pair", rate.Pair.String()),
+			slog.String("error", err.Error()),
+		)
+		return rate, nil
+	}
+
+	r.mu.Lock()
+	r.recorded[key] = rate.AsOf
+	r.mu.Unlock()
+
+	return rate, nil
+}
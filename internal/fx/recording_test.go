@@ -0,0 +1,52 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type memoryRecorder struct {
+	rates []Rate
+	err   error
+}
+
+func (m *memoryRecorder) RecordRate(_ context.Context, rate Rate) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.rates = append(m.rates, rate)
+	return nil
+}
+
+func TestRecordingProvider(t *testing.T) {
+	ctx := context.Background()
+	static, _ := NewStaticProvider(map[string]string{"USDBRL": "5.33"})
+	recorder := &memoryRecorder{}
+	provider := NewRecordingProvider(static, recorder)
+
+	for range 3 {
+		if _, err := provider.Rate(ctx, Pair{Base: "USD", Quote: "BRL"}); err != nil {
+			t.Fatalf("Expected a rate, got %v", err)
+		}
+	}
+	if _, err := provider.Rate(ctx, Pair{Base: "BRL", Quote: "USD"}); err != nil {
+		t.Fatalf("Expected the inverse rate, got %v", err)
+	}
+
+	if len(recorder.rates) != 2 {
+		t.Fatalf("Expected each pair to be recorded once, got %d records", len(recorder.rates))
+	}
+	if recorder.rates[0].Pair.String() != "USDBRL" || recorder.rates[1].Pair.String() != "BRLUSD" {
+		t.Errorf("Expected USDBRL then BRLUSD, got %s and %s", recorder.rates[0].Pair, recorder.rates[1].Pair)
+	}
+
+	if _, err := NewRecordingProvider(&failingProvider{}, recorder).Rate(ctx, Pair{Base: "USD", Quote: "BRL"}); err == nil {
+		t.Error("Expected the provider error to be returned")
+	}
+
+	broken := &memoryRecorder{err: errors.New("database down")}
+	if _, err := NewRecordingProvider(static, broken).Rate(ctx, Pair{Base: "USD", Quote: "BRL"}); err != nil {
+		t.Errorf("Expected the rate despite the recorder failing, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+)
+
+// StaticProvider quotes fixed rates, for tests, offline runs and as the last
+// resort of a Chain. A pair missing from the table is answered with the
+// inverse of its reverse when that one is known.
+type StaticProvider struct {
+	rates map[Pair]*big.Rat
+	asOf  time.Time
+}
+
+// NewStaticProvider builds a provider from decimal rates keyed by pair, as in
+// {"USDBRL": "5.33"}.
+func NewStaticProvider(rates map[string]string) (*StaticProvider, error) {
+	provider := &StaticProvider{rates: make(map[Pair]*big.Rat, len(rates)), asOf: time.Now()}
+	for key, value := range rates {
+		pair, err := ParsePair(key)
+		if err != nil {
+			return nil, err
+		}
+		rate, err := ParseRate(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s", err, pair)
+		}
+		provider.rates[pair] = rate
+	}
+	return provider, nil
+}
+
+// LoadStaticProvider reads the rates of a StaticProvider from a JSON file
+// shaped like the map NewStaticProvider takes.
+func LoadStaticProvider(path string) (*StaticProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rates file: %w", err)
+	}
+
+	var rates map[string]string
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("error decoding rates file %s: %w", path, err)
+	}
+
+	return NewStaticProvider(rates)
+}
+
+func (s *StaticProvider) Rate(_ context.Context, pair Pair) (Rate, error) {
+	if pair.Base == pair.Quote {
+		return Rate{Pair: pair, Value: big.NewRat(1, 1), Source: "static", AsOf: s.asOf}, nil
+	}
+
+	if value, ok := s.rates[pair]; ok {
+		return Rate{Pair: pair, Value: new(big.Rat).Set(value), Source: "static", AsOf: s.asOf}, nil
+	}
+	if value, ok := s.rates[pair.Inverse()]; ok {
+		return Rate{Pair: pair.Inverse(), Value: new(big.Rat).Set(value), Source: "static", AsOf: s.asOf}.Inverse(), nil
+	}
+
+	return Rate{}, fmt.Errorf("%w: %s", ErrRateUnavailable, pair)
+}
@@ -0,0 +1,62 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStaticProvider(t *testing.T) {
+	provider, err := NewStaticProvider(map[string]string{"USDBRL": "5"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		pair Pair
+		want *big.Rat
+	}{
+		{pair: Pair{Base: "USD", Quote: "BRL"}, want: big.NewRat(5, 1)},
+		{pair: Pair{Base: "BRL", Quote: "USD"}, want: big.NewRat(1, 5)},
+		{pair: Pair{Base: "USD", Quote: "USD"}, want: big.NewRat(1, 1)},
+	}
+	for _, tt := range tests {
+		rate, err := provider.Rate(context.Background(), tt.pair)
+		if err != nil {
+			t.Fatalf("Expected a rate for %s, got %v", tt.pair, err)
+		}
+		if rate.Pair != tt.pair || rate.Value.Cmp(tt.want) != 0 {
+			t.Errorf("Expected %s at %s, got %s at %s", tt.pair, tt.want, rate.Pair, rate.Value)
+		}
+	}
+
+	if _, err := provider.Rate(context.Background(), Pair{Base: "USD", Quote: "EUR"}); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("Expected ErrRateUnavailable for an unknown pair, got %v", err)
+	}
+}
+
+func TestLoadStaticProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(`{"USDBRL": "5.33"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := LoadStaticProvider(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	rate, err := provider.Rate(context.Background(), Pair{Base: "USD", Quote: "BRL"})
+	if err != nil || rate.Decimal() != "5.33" {
+		t.Errorf("Expected USDBRL at 5.33, got %v, %v", rate.Value, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"USDBRL": "-1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadStaticProvider(path); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Expected ErrInvalidRate for a negative rate, got %v", err)
+	}
+}
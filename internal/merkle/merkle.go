@@ -0,0 +1,133 @@
+// Package merkle builds binary Merkle trees over SHA-256 and the inclusion
+// proofs that tie a single leaf to the root.
+//
+// Leaves and inner nodes are hashed with distinct prefixes, as in RFC 6962,
+// so a leaf can never be passed off as an inner node. A node without a
+// sibling is promoted to the next level unchanged instead of being paired
+// with itself, which keeps two different leaf lists from sharing a root.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+var (
+	ErrEmptyTree  error = errors.New("merkle tree has no leaves")
+	ErrNoSuchLeaf error = errors.New("leaf index out of range")
+)
+
+// Step is one sibling on the path from a leaf to the root. Left tells on
+// which side the sibling is hashed.
+type Step struct {
+	Hash []byte
+	Left bool
+}
+
+// Tree keeps every level of the tree, leaves first, so proofs can be read
+// off without rehashing.
+type Tree struct {
+	levels [][][]byte
+}
+
+// LeafHash hashes raw leaf data
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// NodeHash hashes two children into their parent
+func NodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// New builds the tree over the given leaf data, in order
+func New(leaves [][]byte) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTree
+	}
+
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = LeafHash(leaf)
+	}
+
+	tree := &Tree{levels: [][][]byte{level}}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, NodeHash(level[i], level[i+1]))
+		}
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+
+	return tree, nil
+}
+
+// Root returns the hash at the top of the tree
+func (t *Tree) Root() []byte {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Len returns the number of leaves
+func (t *Tree) Len() int {
+	return len(t.levels[0])
+}
+
+// Leaf returns the hash of the leaf at index
+func (t *Tree) Leaf(index int) ([]byte, error) {
+	if index < 0 || index >= t.Len() {
+		return nil, fmt.Errorf("%w: %d of %d", ErrNoSuchLeaf, index, t.Len())
+	}
+	return t.levels[0][index], nil
+}
+
+// Proof returns the siblings needed to recompute the root from the leaf at
+// index, bottom up. Levels where the node was promoted contribute no step.
+func (t *Tree) Proof(index int) ([]Step, error) {
+	if index < 0 || index >= t.Len() {
+		return nil, fmt.Errorf("%w: %d of %d", ErrNoSuchLeaf, index, t.Len())
+	}
+
+	var proof []Step
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, Step{Hash: level[sibling], Left: sibling < index})
+		}
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// Verify reports whether the leaf hash and the proof recompute root
+func Verify(root, leaf []byte, proof []Step) bool {
+	hash := leaf
+	for _, step := range proof {
+		if step.Left {
+			hash = NodeHash(step.Hash, hash)
+		} else {
+			hash = NodeHash(hash, step.Hash)
+		}
+	}
+	return bytes.Equal(hash, root)
+}
@@ -0,0 +1,78 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func leaves(n int) [][]byte {
+	data := make([][]byte, n)
+	for i := range data {
+		data[i] = []byte(fmt.Sprintf("transaction-%d", i))
+	}
+	return data
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil); !errors.Is(err, ErrEmptyTree) {
+		t.Errorf("Expected ErrEmptyTree, got %v", err)
+	}
+
+	single, _ := New(leaves(1))
+	if !bytes.Equal(single.Root(), LeafHash([]byte("transaction-0"))) {
+		t.Error("Expected the root of a single leaf to be its leaf hash")
+	}
+
+	three, _ := New(leaves(3))
+	a, b, c := LeafHash([]byte("transaction-0")), LeafHash([]byte("transaction-1")), LeafHash([]byte("transaction-2"))
+	if want := NodeHash(NodeHash(a, b), c); !bytes.Equal(three.Root(), want) {
+		t.Error("Expected the odd leaf to be promoted, not paired with itself")
+	}
+
+	// Duplicating the odd leaf must not produce the same root
+	four, _ := New(append(leaves(3), []byte("transaction-2")))
+	if bytes.Equal(three.Root(), four.Root()) {
+		t.Error("Expected a duplicated trailing leaf to change the root")
+	}
+}
+
+func TestProof(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		tree, err := New(leaves(n))
+		if err != nil {
+			t.Fatalf("Expected no error building %d leaves, got %v", n, err)
+		}
+
+		for i := 0; i < n; i++ {
+			proof, err := tree.Proof(i)
+			if err != nil {
+				t.Fatalf("Expected a proof for leaf %d of %d, got %v", i, n, err)
+			}
+			leaf, _ := tree.Leaf(i)
+			if !Verify(tree.Root(), leaf, proof) {
+				t.Errorf("Expected the proof of leaf %d of %d to verify", i, n)
+			}
+			if Verify(tree.Root(), LeafHash([]byte("forged")), proof) {
+				t.Errorf("Expected a forged leaf to fail against the proof of leaf %d of %d", i, n)
+			}
+		}
+	}
+}
+
+func TestProofOutOfRange(t *testing.T) {
+	tree, _ := New(leaves(4))
+	for _, index := range []int{-1, 4} {
+		if _, err := tree.Proof(index); !errors.Is(err, ErrNoSuchLeaf) {
+			t.Errorf("Expected ErrNoSuchLeaf for index %d, got %v", index, err)
+		}
+	}
+}
+
+func TestLeafIsNotANode(t *testing.T) {
+	a, b := LeafHash([]byte("a")), LeafHash([]byte("b"))
+	if bytes.Equal(NodeHash(a, b), LeafHash(append(append([]byte{}, a...), b...))) {
+		t.Error("Expected leaf and node hashes to be domain separated")
+	}
+}
@@ -12,6 +12,51 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccountClass string
+
+const (
+	AccountClassAsset     AccountClass = "asset"
+	AccountClassLiability AccountClass = "liability"
+	AccountClassEquity    AccountClass = "equity"
+	AccountClassRevenue   AccountClass = "revenue"
+	AccountClassExpense   AccountClass = "expense"
+)
+
+func (e *AccountClass) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountClass(s)
+	case string:
+		*e = AccountClass(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountClass: %T", src)
+	}
+	return nil
+}
+
+type NullAccountClass struct {
+	AccountClass AccountClass
+	Valid        bool // Valid is true if AccountClass is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountClass) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountClass, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountClass.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountClass) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccountClass), nil
+}
+
 type Currency string
 
 const (
@@ -55,11 +100,36 @@ func (ns NullCurrency) Value() (driver.Value, error) {
 }
 
 type Account struct {
-	ID        uuid.UUID
-	Name      string
+	ID             uuid.UUID
+	Name           string
+	Currency       Currency
+	Metadata       []byte
+	CreatedAt      pgtype.Timestamptz
+	Status         string
+	ClosedAt       pgtype.Timestamptz
+	BalancePolicy  string
+	OverdraftLimit int64
+	AccountClass   AccountClass
+	NormalBalance  string
+	ParentID       uuid.NullUUID
+}
+
+type AccountBalance struct {
+	AccountID   uuid.UUID
+	Currency    Currency
+	Balance     int64
+	Version     int64
+	LastEntryID uuid.NullUUID
+	UpdatedAt   pgtype.Timestamptz
+	Pending     int64
+}
+
+type BalanceSnapshot struct {
+	AccountID uuid.UUID
 	Currency  Currency
-	Metadata  []byte
-	CreatedAt pgtype.Timestamptz
+	Balance   int64
+	UpToSeq   int64
+	TakenAt   pgtype.Timestamptz
 }
 
 type Entry struct {
@@ -70,13 +140,94 @@ type Entry struct {
 	Currency      Currency
 	Metadata      []byte
 	CreatedAt     pgtype.Timestamptz
+	EffectiveAt   pgtype.Timestamptz
+	Seq           int64
+}
+
+type FeeSchedule struct {
+	ID              uuid.UUID
+	TransactionType string
+	Currency        Currency
+	Kind            string
+	Bps             int64
+	FlatAmount      int64
+	MinAmount       pgtype.Int8
+	MaxAmount       pgtype.Int8
+	Tiers           []byte
+	Active          bool
+	CreatedAt       pgtype.Timestamptz
+}
+
+type FxQuote struct {
+	ID             uuid.UUID
+	SourceCurrency Currency
+	TargetCurrency Currency
+	SourceAmount   int64
+	TargetAmount   int64
+	MidRate        pgtype.Numeric
+	SpreadBps      int32
+	Rate           pgtype.Numeric
+	RateSource     string
+	ExpiresAt      pgtype.Timestamptz
+	TransactionID  uuid.NullUUID
+	UsedAt         pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+}
+
+type FxRate struct {
+	ID        uuid.UUID
+	Base      Currency
+	Quote     Currency
+	Rate      pgtype.Numeric
+	Source    string
+	AsOf      pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type FxSpread struct {
+	ID        uuid.UUID
+	Base      Currency
+	Quote     Currency
+	Bps       int32
+	Tiers     []byte
+	Active    bool
+	CreatedAt pgtype.Timestamptz
+}
+
+type Hold struct {
+	ID             uuid.UUID
+	TransactionID  uuid.UUID
+	AccountID      uuid.UUID
+	CounterpartyID uuid.UUID
+	Currency       Currency
+	Amount         int64
+	CapturedAmount int64
+	Status         string
+	ExpiresAt      pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+}
+
+type LedgerAttestation struct {
+	Day           pgtype.Date
+	Root          []byte
+	LeafCount     int64
+	FirstChainSeq int64
+	LastChainSeq  int64
+	CreatedAt     pgtype.Timestamptz
 }
 
 type Transaction struct {
-	ID          uuid.UUID
-	ExternalID  pgtype.Text
-	Description pgtype.Text
-	Status      string
-	CreatedBy   pgtype.Text
-	CreatedAt   pgtype.Timestamptz
+	ID                    uuid.UUID
+	ExternalID            pgtype.Text
+	Description           pgtype.Text
+	Status                string
+	CreatedBy             pgtype.Text
+	CreatedAt             pgtype.Timestamptz
+	ReversesTransactionID uuid.NullUUID
+	Metadata              []byte
+	Hash                  []byte
+	PrevHash              []byte
+	ChainSeq              pgtype.Int8
+	LinkedAt              pgtype.Timestamptz
 }
@@ -8,13 +8,77 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	ApplyBalanceDelta(ctx context.Context, arg ApplyBalanceDeltaParams) error
+	ApplyPendingDelta(ctx context.Context, arg ApplyPendingDeltaParams) error
+	CloseAccount(ctx context.Context, id uuid.UUID) (Account, error)
+	CountUnchainedTransactions(ctx context.Context, before pgtype.Timestamptz) (int64, error)
+	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountBalance(ctx context.Context, arg CreateAccountBalanceParams) error
+	CreateAttestation(ctx context.Context, arg CreateAttestationParams) error
+	CreateBalanceSnapshots(ctx context.Context, arg CreateBalanceSnapshotsParams) (int64, error)
+	CreateEntry(ctx context.Context, arg CreateEntryParams) (CreateEntryRow, error)
+	CreateFxQuote(ctx context.Context, arg CreateFxQuoteParams) (FxQuote, error)
+	CreateFxRate(ctx context.Context, arg CreateFxRateParams) error
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
+	GetAccount(ctx context.Context, id uuid.UUID) (Account, error)
+	GetAccountBalanceAsOf(ctx context.Context, arg GetAccountBalanceAsOfParams) (GetAccountBalanceAsOfRow, error)
+	GetAccountBalanceForUpdate(ctx context.Context, accountID uuid.UUID) (int64, error)
+	GetAccountBalances(ctx context.Context, ids []uuid.UUID) ([]GetAccountBalancesRow, error)
+	GetAccountForUpdate(ctx context.Context, id uuid.UUID) (Account, error)
+	GetAccountsByIDs(ctx context.Context, ids []uuid.UUID) ([]Account, error)
+	GetAccountStatement(ctx context.Context, arg GetAccountStatementParams) ([]GetAccountStatementRow, error)
+	GetAccountTotals(ctx context.Context, arg GetAccountTotalsParams) ([]GetAccountTotalsRow, error)
+	GetAccountTree(ctx context.Context, id uuid.UUID) ([]GetAccountTreeRow, error)
+	GetActiveFeeSchedules(ctx context.Context, arg GetActiveFeeSchedulesParams) ([]FeeSchedule, error)
+	GetActiveFxSpread(ctx context.Context, arg GetActiveFxSpreadParams) (FxSpread, error)
 	GetAllAccounts(ctx context.Context) ([]Account, error)
 	GetAllEntries(ctx context.Context) ([]Entry, error)
 	GetAllTransactions(ctx context.Context) ([]Transaction, error)
-	GetUserFunds(ctx context.Context, accountID uuid.UUID) (int64, error)
+	GetAttestation(ctx context.Context, day pgtype.Date) (LedgerAttestation, error)
+	GetAttestationByChainSeq(ctx context.Context, chainSeq int64) (LedgerAttestation, error)
+	GetBalanceDrift(ctx context.Context) ([]GetBalanceDriftRow, error)
+	GetDuplicateExternalIDs(ctx context.Context) ([]GetDuplicateExternalIDsRow, error)
+	GetEntriesByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]GetEntriesByTransactionIDRow, error)
+	GetEntriesByTransactionIDs(ctx context.Context, transactionIds []uuid.UUID) ([]GetEntriesByTransactionIDsRow, error)
+	GetEntryCurrencyMismatches(ctx context.Context) ([]GetEntryCurrencyMismatchesRow, error)
+	GetExpiredHoldIDs(ctx context.Context, limit int32) ([]uuid.UUID, error)
+	GetForeignDailyFlows(ctx context.Context, arg GetForeignDailyFlowsParams) ([]GetForeignDailyFlowsRow, error)
+	GetFxQuote(ctx context.Context, id uuid.UUID) (FxQuote, error)
+	GetFxRateAt(ctx context.Context, arg GetFxRateAtParams) (FxRate, error)
+	GetFxRateHistory(ctx context.Context, arg GetFxRateHistoryParams) ([]FxRate, error)
+	GetHashChain(ctx context.Context, arg GetHashChainParams) ([]Transaction, error)
+	GetHashChainHead(ctx context.Context) (GetHashChainHeadRow, error)
+	GetHold(ctx context.Context, id uuid.UUID) (Hold, error)
+	GetHoldByTransactionID(ctx context.Context, transactionID uuid.UUID) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id uuid.UUID) (Hold, error)
+	GetLastAttestedChainSeq(ctx context.Context) (int64, error)
+	GetLastChainSeqBefore(ctx context.Context, arg GetLastChainSeqBeforeParams) (int64, error)
+	GetLinkedTransactions(ctx context.Context, arg GetLinkedTransactionsParams) ([]Transaction, error)
+	GetOrphanTransactions(ctx context.Context) ([]GetOrphanTransactionsRow, error)
+	GetReversedEntryAmounts(ctx context.Context, reversesTransactionID uuid.NullUUID) ([]GetReversedEntryAmountsRow, error)
+	GetSnapshotDrift(ctx context.Context) ([]GetSnapshotDriftRow, error)
+	GetTransaction(ctx context.Context, id uuid.UUID) (Transaction, error)
+	GetTransactionByExternalID(ctx context.Context, externalID pgtype.Text) (Transaction, error)
+	GetTransactionForUpdate(ctx context.Context, id uuid.UUID) (Transaction, error)
+	GetUnattestedDays(ctx context.Context, cutoff pgtype.Timestamptz) ([]pgtype.Date, error)
+	GetUnbalancedTransactions(ctx context.Context) ([]GetUnbalancedTransactionsRow, error)
+	GetUnchainedTransactionIDs(ctx context.Context, limit int32) ([]uuid.UUID, error)
+	LinkTransaction(ctx context.Context, arg LinkTransactionParams) error
+	LockAccountTree(ctx context.Context) error
+	LockHashChain(ctx context.Context) error
+	PostPendingTransaction(ctx context.Context, id uuid.UUID) (Transaction, error)
+	SearchTransactions(ctx context.Context, arg SearchTransactionsParams) ([]Transaction, error)
+	UpdateAccountBalancePolicy(ctx context.Context, arg UpdateAccountBalancePolicyParams) (Account, error)
+	UpdateAccountMetadata(ctx context.Context, arg UpdateAccountMetadataParams) (Account, error)
+	UpdateAccountParent(ctx context.Context, arg UpdateAccountParentParams) (Account, error)
+	UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) (Hold, error)
+	UpdateTransactionStatus(ctx context.Context, arg UpdateTransactionStatusParams) error
+	UseFxQuote(ctx context.Context, arg UseFxQuoteParams) (FxQuote, error)
 }
 
 var _ Querier = (*Queries)(nil)
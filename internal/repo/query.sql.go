@@ -9,14 +9,583 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const getAllAccounts = `-- name: GetAllAccounts :many
-SELECT id, name, currency, metadata, created_at from accounts
+const applyBalanceDelta = `-- name: ApplyBalanceDelta :exec
+INSERT INTO account_balances (account_id, currency, balance, version, last_entry_id, updated_at)
+VALUES ($1, $2, $3, 1, $4, NOW())
+ON CONFLICT (account_id) DO UPDATE
+SET balance = account_balances.balance + EXCLUDED.balance,
+    version = account_balances.version + 1,
+    last_entry_id = EXCLUDED.last_entry_id,
+    updated_at = NOW()
 `
 
-func (q *Queries) GetAllAccounts(ctx context.Context) ([]Account, error) {
-	rows, err := q.db.Query(ctx, getAllAccounts)
+type ApplyBalanceDeltaParams struct {
+	AccountID   uuid.UUID
+	Currency    Currency
+	Balance     int64
+	LastEntryID uuid.NullUUID
+}
+
+func (q *Queries) ApplyBalanceDelta(ctx context.Context, arg ApplyBalanceDeltaParams) error {
+	_, err := q.db.Exec(ctx, applyBalanceDelta,
+		arg.AccountID,
+		arg.Currency,
+		arg.Balance,
+		arg.LastEntryID,
+	)
+	return err
+}
+
+const applyPendingDelta = `-- name: ApplyPendingDelta :exec
+INSERT INTO account_balances (account_id, currency, pending, updated_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (account_id) DO UPDATE
+SET pending = account_balances.pending + EXCLUDED.pending,
+    updated_at = NOW()
+`
+
+type ApplyPendingDeltaParams struct {
+	AccountID uuid.UUID
+	Currency  Currency
+	Pending   int64
+}
+
+func (q *Queries) ApplyPendingDelta(ctx context.Context, arg ApplyPendingDeltaParams) error {
+	_, err := q.db.Exec(ctx, applyPendingDelta, arg.AccountID, arg.Currency, arg.Pending)
+	return err
+}
+
+const closeAccount = `-- name: CloseAccount :one
+UPDATE accounts SET status = 'closed', closed_at = NOW() WHERE id = $1 RETURNING id, name, currency, metadata, created_at, status, closed_at, balance_policy, overdraft_limit, account_class, normal_balance, parent_id
+`
+
+func (q *Queries) CloseAccount(ctx context.Context, id uuid.UUID) (Account, error) {
+	row := q.db.QueryRow(ctx, closeAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.BalancePolicy,
+		&i.OverdraftLimit,
+		&i.AccountClass,
+		&i.NormalBalance,
+		&i.ParentID,
+	)
+	return i, err
+}
+
+const countUnchainedTransactions = `-- name: CountUnchainedTransactions :one
+SELECT COUNT(*) FROM transactions
+WHERE chain_seq IS NULL AND status NOT IN ('pending', 'voided')
+  AND created_at < $1::TIMESTAMPTZ
+`
+
+func (q *Queries) CountUnchainedTransactions(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnchainedTransactions, before)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAccount = `-- name: CreateAccount :one
+INSERT INTO accounts (id, name, currency, metadata, balance_policy, overdraft_limit, account_class, normal_balance, parent_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, name, currency, metadata, created_at, status, closed_at, balance_policy, overdraft_limit, account_class, normal_balance, parent_id
+`
+
+type CreateAccountParams struct {
+	ID             uuid.UUID
+	Name           string
+	Currency       Currency
+	Metadata       []byte
+	BalancePolicy  string
+	OverdraftLimit int64
+	AccountClass   AccountClass
+	NormalBalance  string
+	ParentID       uuid.NullUUID
+}
+
+func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	row := q.db.QueryRow(ctx, createAccount,
+		arg.ID,
+		arg.Name,
+		arg.Currency,
+		arg.Metadata,
+		arg.BalancePolicy,
+		arg.OverdraftLimit,
+		arg.AccountClass,
+		arg.NormalBalance,
+		arg.ParentID,
+	)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.BalancePolicy,
+		&i.OverdraftLimit,
+		&i.AccountClass,
+		&i.NormalBalance,
+		&i.ParentID,
+	)
+	return i, err
+}
+
+const createAccountBalance = `-- name: CreateAccountBalance :exec
+INSERT INTO account_balances (account_id, currency) VALUES ($1, $2)
+`
+
+type CreateAccountBalanceParams struct {
+	AccountID uuid.UUID
+	Currency  Currency
+}
+
+func (q *Queries) CreateAccountBalance(ctx context.Context, arg CreateAccountBalanceParams) error {
+	_, err := q.db.Exec(ctx, createAccountBalance, arg.AccountID, arg.Currency)
+	return err
+}
+
+const createAttestation = `-- name: CreateAttestation :exec
+INSERT INTO ledger_attestations (day, root, leaf_count, first_chain_seq, last_chain_seq)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (day) DO NOTHING
+`
+
+type CreateAttestationParams struct {
+	Day           pgtype.Date
+	Root          []byte
+	LeafCount     int64
+	FirstChainSeq int64
+	LastChainSeq  int64
+}
+
+func (q *Queries) CreateAttestation(ctx context.Context, arg CreateAttestationParams) error {
+	_, err := q.db.Exec(ctx, createAttestation,
+		arg.Day,
+		arg.Root,
+		arg.LeafCount,
+		arg.FirstChainSeq,
+		arg.LastChainSeq,
+	)
+	return err
+}
+
+const createBalanceSnapshots = `-- name: CreateBalanceSnapshots :execrows
+WITH settled AS (
+    -- A posting commits within settle of its start, so every seq handed out
+    -- before an entry whose transaction started two settle periods ago is
+    -- final, whatever the order in which the postings started
+    SELECT seq FROM entries
+    WHERE created_at < NOW() - 2 * $1::INTERVAL
+    ORDER BY seq DESC
+    LIMIT 1
+)
+INSERT INTO balance_snapshots (account_id, currency, balance, up_to_seq)
+SELECT entries.account_id,
+       accounts.currency,
+       (COALESCE(last.balance, 0) + SUM(entries.amount))::BIGINT,
+       MAX(entries.seq)
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+LEFT JOIN LATERAL (
+    SELECT balance, up_to_seq FROM balance_snapshots
+    WHERE balance_snapshots.account_id = entries.account_id
+    ORDER BY up_to_seq DESC
+    LIMIT 1
+) last ON TRUE
+WHERE entries.seq > COALESCE(last.up_to_seq, 0)
+  AND entries.seq <= (SELECT seq FROM settled)
+GROUP BY entries.account_id, accounts.currency, last.balance
+HAVING COUNT(*) >= $2::BIGINT
+`
+
+type CreateBalanceSnapshotsParams struct {
+	Settle     pgtype.Interval
+	MinEntries int64
+}
+
+func (q *Queries) CreateBalanceSnapshots(ctx context.Context, arg CreateBalanceSnapshotsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createBalanceSnapshots, arg.Settle, arg.MinEntries)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createEntry = `-- name: CreateEntry :one
+INSERT INTO entries (id, transaction_id, account_id, amount, currency, metadata, effective_at)
+VALUES ($1, $2, $3, $4::BIGINT, $5, $6, COALESCE($7::TIMESTAMPTZ, NOW()))
+RETURNING id, transaction_id, account_id, amount::BIGINT AS amount, currency, metadata, created_at, effective_at
+`
+
+type CreateEntryParams struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	AccountID     uuid.UUID
+	Amount        int64
+	Currency      Currency
+	Metadata      []byte
+	EffectiveAt   pgtype.Timestamptz
+}
+
+type CreateEntryRow struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	AccountID     uuid.UUID
+	Amount        int64
+	Currency      Currency
+	Metadata      []byte
+	CreatedAt     pgtype.Timestamptz
+	EffectiveAt   pgtype.Timestamptz
+}
+
+func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (CreateEntryRow, error) {
+	row := q.db.QueryRow(ctx, createEntry,
+		arg.ID,
+		arg.TransactionID,
+		arg.AccountID,
+		arg.Amount,
+		arg.Currency,
+		arg.Metadata,
+		arg.EffectiveAt,
+	)
+	var i CreateEntryRow
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.EffectiveAt,
+	)
+	return i, err
+}
+
+const createFxQuote = `-- name: CreateFxQuote :one
+INSERT INTO fx_quotes (id, source_currency, target_currency, source_amount, target_amount, mid_rate, spread_bps, rate, rate_source, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, source_currency, target_currency, source_amount, target_amount, mid_rate, spread_bps, rate, rate_source, expires_at, transaction_id, used_at, created_at
+`
+
+type CreateFxQuoteParams struct {
+	ID             uuid.UUID
+	SourceCurrency Currency
+	TargetCurrency Currency
+	SourceAmount   int64
+	TargetAmount   int64
+	MidRate        pgtype.Numeric
+	SpreadBps      int32
+	Rate           pgtype.Numeric
+	RateSource     string
+	ExpiresAt      pgtype.Timestamptz
+}
+
+func (q *Queries) CreateFxQuote(ctx context.Context, arg CreateFxQuoteParams) (FxQuote, error) {
+	row := q.db.QueryRow(ctx, createFxQuote,
+		arg.ID,
+		arg.SourceCurrency,
+		arg.TargetCurrency,
+		arg.SourceAmount,
+		arg.TargetAmount,
+		arg.MidRate,
+		arg.SpreadBps,
+		arg.Rate,
+		arg.RateSource,
+		arg.ExpiresAt,
+	)
+	var i FxQuote
+	err := row.Scan(
+		&i.ID,
+		&i.SourceCurrency,
+		&i.TargetCurrency,
+		&i.SourceAmount,
+		&i.TargetAmount,
+		&i.MidRate,
+		&i.SpreadBps,
+		&i.Rate,
+		&i.RateSource,
+		&i.ExpiresAt,
+		&i.TransactionID,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createFxRate = `-- name: CreateFxRate :exec
+INSERT INTO fx_rates (id, base, quote, rate, source, as_of)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (base, quote, source, as_of) DO NOTHING
+`
+
+type CreateFxRateParams struct {
+	ID     uuid.UUID
+	Base   Currency
+	Quote  Currency
+	Rate   pgtype.Numeric
+	Source string
+	AsOf   pgtype.Timestamptz
+}
+
+func (q *Queries) CreateFxRate(ctx context.Context, arg CreateFxRateParams) error {
+	_, err := q.db.Exec(ctx, createFxRate,
+		arg.ID,
+		arg.Base,
+		arg.Quote,
+		arg.Rate,
+		arg.Source,
+		arg.AsOf,
+	)
+	return err
+}
+
+const createHold = `-- name: CreateHold :one
+INSERT INTO holds (id, transaction_id, account_id, counterparty_id, currency, amount, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, transaction_id, account_id, counterparty_id, currency, amount, captured_amount, status, expires_at, created_at, updated_at
+`
+
+type CreateHoldParams struct {
+	ID             uuid.UUID
+	TransactionID  uuid.UUID
+	AccountID      uuid.UUID
+	CounterpartyID uuid.UUID
+	Currency       Currency
+	Amount         int64
+	ExpiresAt      pgtype.Timestamptz
+}
+
+func (q *Queries) CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error) {
+	row := q.db.QueryRow(ctx, createHold,
+		arg.ID,
+		arg.TransactionID,
+		arg.AccountID,
+		arg.CounterpartyID,
+		arg.Currency,
+		arg.Amount,
+		arg.ExpiresAt,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.CounterpartyID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createTransaction = `-- name: CreateTransaction :one
+INSERT INTO transactions (id, external_id, description, status, created_by, reverses_transaction_id, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (external_id) DO NOTHING
+RETURNING id, external_id, description, status, created_by, created_at, reverses_transaction_id, metadata, hash, prev_hash, chain_seq, linked_at
+`
+
+type CreateTransactionParams struct {
+	ID                    uuid.UUID
+	ExternalID            pgtype.Text
+	Description           pgtype.Text
+	Status                string
+	CreatedBy             pgtype.Text
+	ReversesTransactionID uuid.NullUUID
+	Metadata              []byte
+}
+
+func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
+	row := q.db.QueryRow(ctx, createTransaction,
+		arg.ID,
+		arg.ExternalID,
+		arg.Description,
+		arg.Status,
+		arg.CreatedBy,
+		arg.ReversesTransactionID,
+		arg.Metadata,
+	)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.Description,
+		&i.Status,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ReversesTransactionID,
+		&i.Metadata,
+		&i.Hash,
+		&i.PrevHash,
+		&i.ChainSeq,
+		&i.LinkedAt,
+	)
+	return i, err
+}
+
+const getAccount = `-- name: GetAccount :one
+SELECT id, name, currency, metadata, created_at, status, closed_at, balance_policy, overdraft_limit, account_class, normal_balance, parent_id FROM accounts WHERE id = $1
+`
+
+func (q *Queries) GetAccount(ctx context.Context, id uuid.UUID) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.BalancePolicy,
+		&i.OverdraftLimit,
+		&i.AccountClass,
+		&i.NormalBalance,
+		&i.ParentID,
+	)
+	return i, err
+}
+
+const getAccountBalanceAsOf = `-- name: GetAccountBalanceAsOf :one
+WITH snapshot AS (
+    SELECT balance, up_to_seq FROM balance_snapshots
+    WHERE account_id = $1 AND taken_at <= $2
+    ORDER BY up_to_seq DESC
+    LIMIT 1
+)
+SELECT accounts.currency,
+       (COALESCE((SELECT balance FROM snapshot), 0)
+        + COALESCE((SELECT SUM(amount) FROM entries
+                    WHERE account_id = accounts.id
+                      AND seq > COALESCE((SELECT up_to_seq FROM snapshot), 0)
+                      AND effective_at <= $2), 0)
+        - COALESCE((SELECT SUM(amount) FROM entries
+                    WHERE account_id = accounts.id
+                      AND seq <= COALESCE((SELECT up_to_seq FROM snapshot), 0)
+                      AND effective_at > $2), 0))::BIGINT AS balance
+FROM accounts
+WHERE accounts.id = $1
+`
+
+type GetAccountBalanceAsOfParams struct {
+	AccountID uuid.UUID
+	AsOf      pgtype.Timestamptz
+}
+
+type GetAccountBalanceAsOfRow struct {
+	Currency Currency
+	Balance  int64
+}
+
+func (q *Queries) GetAccountBalanceAsOf(ctx context.Context, arg GetAccountBalanceAsOfParams) (GetAccountBalanceAsOfRow, error) {
+	row := q.db.QueryRow(ctx, getAccountBalanceAsOf, arg.AccountID, arg.AsOf)
+	var i GetAccountBalanceAsOfRow
+	err := row.Scan(&i.Currency, &i.Balance)
+	return i, err
+}
+
+const getAccountBalanceForUpdate = `-- name: GetAccountBalanceForUpdate :one
+SELECT (balance - pending)::BIGINT AS available FROM account_balances WHERE account_id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetAccountBalanceForUpdate(ctx context.Context, accountID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, getAccountBalanceForUpdate, accountID)
+	var available int64
+	err := row.Scan(&available)
+	return available, err
+}
+
+const getAccountBalances = `-- name: GetAccountBalances :many
+SELECT accounts.id AS account_id,
+       accounts.currency,
+       COALESCE(account_balances.balance, 0)::BIGINT AS balance,
+       COALESCE(account_balances.pending, 0)::BIGINT AS pending
+FROM accounts
+LEFT JOIN account_balances ON account_balances.account_id = accounts.id
+WHERE accounts.id = ANY($1::UUID[])
+`
+
+type GetAccountBalancesRow struct {
+	AccountID uuid.UUID
+	Currency  Currency
+	Balance   int64
+	Pending   int64
+}
+
+func (q *Queries) GetAccountBalances(ctx context.Context, ids []uuid.UUID) ([]GetAccountBalancesRow, error) {
+	rows, err := q.db.Query(ctx, getAccountBalances, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAccountBalancesRow
+	for rows.Next() {
+		var i GetAccountBalancesRow
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Currency,
+			&i.Balance,
+			&i.Pending,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAccountForUpdate = `-- name: GetAccountForUpdate :one
+SELECT id, name, currency, metadata, created_at, status, closed_at, balance_policy, overdraft_limit, account_class, normal_balance, parent_id FROM accounts WHERE id = $1 FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetAccountForUpdate(ctx context.Context, id uuid.UUID) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountForUpdate, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.BalancePolicy,
+		&i.OverdraftLimit,
+		&i.AccountClass,
+		&i.NormalBalance,
+		&i.ParentID,
+	)
+	return i, err
+}
+
+const getAccountsByIDs = `-- name: GetAccountsByIDs :many
+SELECT id, name, currency, metadata, created_at, status, closed_at, balance_policy, overdraft_limit, account_class, normal_balance, parent_id FROM accounts WHERE id = ANY($1::UUID[])
+`
+
+func (q *Queries) GetAccountsByIDs(ctx context.Context, ids []uuid.UUID) ([]Account, error) {
+	rows, err := q.db.Query(ctx, getAccountsByIDs, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +599,13 @@ func (q *Queries) GetAllAccounts(ctx context.Context) ([]Account, error) {
 			&i.Currency,
 			&i.Metadata,
 			&i.CreatedAt,
+			&i.Status,
+			&i.ClosedAt,
+			&i.BalancePolicy,
+			&i.OverdraftLimit,
+			&i.AccountClass,
+			&i.NormalBalance,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -41,27 +617,121 @@ func (q *Queries) GetAllAccounts(ctx context.Context) ([]Account, error) {
 	return items, nil
 }
 
-const getAllEntries = `-- name: GetAllEntries :many
-SELECT id, transaction_id, account_id, amount, currency, metadata, created_at from entries
+const getAccountStatement = `-- name: GetAccountStatement :many
+WITH start AS (
+    -- The balance right after the newest entry of the page: the current
+    -- balance less every entry at or after the cursor, or the end date
+    SELECT (COALESCE((SELECT balance FROM account_balances WHERE account_id = $1), 0)
+            - COALESCE((SELECT SUM(amount) FROM entries
+                        WHERE account_id = $1
+                          AND CASE WHEN $2::TIMESTAMPTZ IS NOT NULL
+                                   THEN (created_at, id) >= ($2, $3::UUID)
+                                   ELSE created_at >= $4::TIMESTAMPTZ
+                              END), 0))::BIGINT AS balance
+),
+ledger AS (
+    SELECT entries.id,
+           entries.transaction_id,
+           entries.amount::BIGINT AS amount,
+           entries.currency,
+           entries.metadata,
+           entries.created_at,
+           entries.effective_at,
+           ((SELECT balance FROM start)
+            - COALESCE(SUM(entries.amount) OVER (
+                ORDER BY entries.created_at DESC, entries.id DESC
+                ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0))::BIGINT AS running_balance
+    FROM entries
+    WHERE entries.account_id = $1
+      AND ($2::TIMESTAMPTZ IS NULL
+           OR (entries.created_at, entries.id) < ($2, $3::UUID))
+      AND ($4::TIMESTAMPTZ IS NULL OR entries.created_at < $4)
+      AND ($5::TIMESTAMPTZ IS NULL OR entries.created_at >= $5)
+)
+SELECT ledger.id,
+       ledger.transaction_id,
+       ledger.amount,
+       ledger.currency,
+       ledger.metadata,
+       ledger.created_at,
+       ledger.effective_at,
+       ledger.running_balance,
+       transactions.description,
+       counterparty.id AS counterparty_id,
+       counterparty.name AS counterparty_name
+FROM ledger
+JOIN transactions ON transactions.id = ledger.transaction_id
+LEFT JOIN LATERAL (
+    SELECT accounts.id, accounts.name
+    FROM entries other
+    JOIN accounts ON accounts.id = other.account_id
+    WHERE other.transaction_id = ledger.transaction_id
+      AND other.account_id <> $1
+      AND SIGN(other.amount) <> SIGN(ledger.amount)
+    ORDER BY ABS(other.amount) DESC, other.id
+    LIMIT 1
+) counterparty ON TRUE
+WHERE ($6::BIGINT IS NULL OR ABS(ledger.amount) >= $6)
+  AND ($7::BIGINT IS NULL OR ABS(ledger.amount) <= $7)
+ORDER BY ledger.created_at DESC, ledger.id DESC
+LIMIT $8
 `
 
-func (q *Queries) GetAllEntries(ctx context.Context) ([]Entry, error) {
-	rows, err := q.db.Query(ctx, getAllEntries)
+type GetAccountStatementParams struct {
+	AccountID       uuid.UUID
+	CursorCreatedAt pgtype.Timestamptz
+	CursorID        uuid.NullUUID
+	ToDate          pgtype.Timestamptz
+	FromDate        pgtype.Timestamptz
+	MinAmount       pgtype.Int8
+	MaxAmount       pgtype.Int8
+	PageSize        int32
+}
+
+type GetAccountStatementRow struct {
+	ID               uuid.UUID
+	TransactionID    uuid.UUID
+	Amount           int64
+	Currency         Currency
+	Metadata         []byte
+	CreatedAt        pgtype.Timestamptz
+	EffectiveAt      pgtype.Timestamptz
+	RunningBalance   int64
+	Description      pgtype.Text
+	CounterpartyID   uuid.NullUUID
+	CounterpartyName pgtype.Text
+}
+
+func (q *Queries) GetAccountStatement(ctx context.Context, arg GetAccountStatementParams) ([]GetAccountStatementRow, error) {
+	rows, err := q.db.Query(ctx, getAccountStatement,
+		arg.AccountID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ToDate,
+		arg.FromDate,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Entry
+	var items []GetAccountStatementRow
 	for rows.Next() {
-		var i Entry
+		var i GetAccountStatementRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.TransactionID,
-			&i.AccountID,
 			&i.Amount,
 			&i.Currency,
 			&i.Metadata,
 			&i.CreatedAt,
+			&i.EffectiveAt,
+			&i.RunningBalance,
+			&i.Description,
+			&i.CounterpartyID,
+			&i.CounterpartyName,
 		); err != nil {
 			return nil, err
 		}
@@ -73,26 +743,222 @@ func (q *Queries) GetAllEntries(ctx context.Context) ([]Entry, error) {
 	return items, nil
 }
 
-const getAllTransactions = `-- name: GetAllTransactions :many
-SELECT id, external_id, description, status, created_by, created_at from transactions
+const getAccountTotals = `-- name: GetAccountTotals :many
+SELECT accounts.id,
+       accounts.name,
+       accounts.currency,
+       accounts.account_class,
+       accounts.normal_balance,
+       COALESCE(SUM(-entries.amount) FILTER (WHERE entries.amount < 0), 0)::BIGINT AS debits,
+       COALESCE(SUM(entries.amount) FILTER (WHERE entries.amount > 0), 0)::BIGINT AS credits
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id
+    AND ($1::TIMESTAMPTZ IS NULL OR entries.effective_at > $1)
+    AND entries.effective_at <= $2
+GROUP BY accounts.id
+ORDER BY accounts.currency, accounts.account_class, accounts.name, accounts.id
 `
 
-func (q *Queries) GetAllTransactions(ctx context.Context) ([]Transaction, error) {
-	rows, err := q.db.Query(ctx, getAllTransactions)
+type GetAccountTotalsParams struct {
+	FromDate pgtype.Timestamptz
+	ToDate   pgtype.Timestamptz
+}
+
+type GetAccountTotalsRow struct {
+	ID            uuid.UUID
+	Name          string
+	Currency      Currency
+	AccountClass  AccountClass
+	NormalBalance string
+	Debits        int64
+	Credits       int64
+}
+
+func (q *Queries) GetAccountTotals(ctx context.Context, arg GetAccountTotalsParams) ([]GetAccountTotalsRow, error) {
+	rows, err := q.db.Query(ctx, getAccountTotals, arg.FromDate, arg.ToDate)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Transaction
+	var items []GetAccountTotalsRow
 	for rows.Next() {
-		var i Transaction
+		var i GetAccountTotalsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.ExternalID,
-			&i.Description,
-			&i.Status,
-			&i.CreatedBy,
+			&i.Name,
+			&i.Currency,
+			&i.AccountClass,
+			&i.NormalBalance,
+			&i.Debits,
+			&i.Credits,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAccountTree = `-- name: GetAccountTree :many
+WITH RECURSIVE tree AS (
+    SELECT accounts.id, 0 AS depth, ARRAY[accounts.id] AS path FROM accounts WHERE accounts.id = $1
+    UNION ALL
+    SELECT accounts.id, tree.depth + 1, tree.path || accounts.id
+    FROM accounts JOIN tree ON accounts.parent_id = tree.id
+    -- Stops at an account already on the path instead of looping forever
+    WHERE accounts.id <> ALL(tree.path)
+)
+SELECT accounts.id,
+       accounts.parent_id,
+       accounts.name,
+       accounts.currency,
+       accounts.account_class,
+       accounts.normal_balance,
+       tree.depth::INT AS depth,
+       COALESCE(account_balances.balance, 0)::BIGINT AS balance
+FROM tree
+JOIN accounts ON accounts.id = tree.id
+LEFT JOIN account_balances ON account_balances.account_id = accounts.id
+ORDER BY tree.depth, accounts.name, accounts.id
+`
+
+type GetAccountTreeRow struct {
+	ID            uuid.UUID
+	ParentID      uuid.NullUUID
+	Name          string
+	Currency      Currency
+	AccountClass  AccountClass
+	NormalBalance string
+	Depth         int32
+	Balance       int64
+}
+
+func (q *Queries) GetAccountTree(ctx context.Context, id uuid.UUID) ([]GetAccountTreeRow, error) {
+	rows, err := q.db.Query(ctx, getAccountTree, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAccountTreeRow
+	for rows.Next() {
+		var i GetAccountTreeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParentID,
+			&i.Name,
+			&i.Currency,
+			&i.AccountClass,
+			&i.NormalBalance,
+			&i.Depth,
+			&i.Balance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveFeeSchedules = `-- name: GetActiveFeeSchedules :many
+SELECT id, transaction_type, currency, kind, bps, flat_amount, min_amount, max_amount, tiers, active, created_at FROM fee_schedules
+WHERE active AND transaction_type = $1 AND currency = $2
+ORDER BY created_at, id
+`
+
+type GetActiveFeeSchedulesParams struct {
+	TransactionType string
+	Currency        Currency
+}
+
+func (q *Queries) GetActiveFeeSchedules(ctx context.Context, arg GetActiveFeeSchedulesParams) ([]FeeSchedule, error) {
+	rows, err := q.db.Query(ctx, getActiveFeeSchedules, arg.TransactionType, arg.Currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeeSchedule
+	for rows.Next() {
+		var i FeeSchedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransactionType,
+			&i.Currency,
+			&i.Kind,
+			&i.Bps,
+			&i.FlatAmount,
+			&i.MinAmount,
+			&i.MaxAmount,
+			&i.Tiers,
+			&i.Active,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveFxSpread = `-- name: GetActiveFxSpread :one
+SELECT id, base, quote, bps, tiers, active, created_at FROM fx_spreads
+WHERE active AND base = $1 AND quote = $2
+`
+
+type GetActiveFxSpreadParams struct {
+	Base  Currency
+	Quote Currency
+}
+
+func (q *Queries) GetActiveFxSpread(ctx context.Context, arg GetActiveFxSpreadParams) (FxSpread, error) {
+	row := q.db.QueryRow(ctx, getActiveFxSpread, arg.Base, arg.Quote)
+	var i FxSpread
+	err := row.Scan(
+		&i.ID,
+		&i.Base,
+		&i.Quote,
+		&i.Bps,
+		&i.Tiers,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAllAccounts = `-- name: GetAllAccounts :many
+SELECT id, name, currency, metadata, created_at, status, closed_at, balance_policy, overdraft_limit, account_class, normal_balance, parent_id from accounts
+`
+
+func (q *Queries) GetAllAccounts(ctx context.Context) ([]Account, error) {
+	rows, err := q.db.Query(ctx, getAllAccounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Currency,
+			&i.Metadata,
 			&i.CreatedAt,
+			&i.Status,
+			&i.ClosedAt,
+			&i.BalancePolicy,
+			&i.OverdraftLimit,
+			&i.AccountClass,
+			&i.NormalBalance,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -104,13 +970,1328 @@ func (q *Queries) GetAllTransactions(ctx context.Context) ([]Transaction, error)
 	return items, nil
 }
 
-const getUserFunds = `-- name: GetUserFunds :one
-SELECT COALESCE(SUM(entries.amount), 0)::BIGINT as Funds from entries where account_id = $1 FOR UPDATE
+const getAllEntries = `-- name: GetAllEntries :many
+SELECT id, transaction_id, account_id, amount, currency, metadata, created_at, effective_at, seq from entries
 `
 
-func (q *Queries) GetUserFunds(ctx context.Context, accountID uuid.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, getUserFunds, accountID)
-	var funds int64
-	err := row.Scan(&funds)
-	return funds, err
+func (q *Queries) GetAllEntries(ctx context.Context) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, getAllEntries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Entry
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransactionID,
+			&i.AccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.EffectiveAt,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllTransactions = `-- name: GetAllTransactions :many
+SELECT id, external_id, description, status, created_by, created_at, reverses_transaction_id, metadata, hash, prev_hash, chain_seq, linked_at from transactions
+`
+
+func (q *Queries) GetAllTransactions(ctx context.Context) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, getAllTransactions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.Description,
+			&i.Status,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.ReversesTransactionID,
+			&i.Metadata,
+			&i.Hash,
+			&i.PrevHash,
+			&i.ChainSeq,
+			&i.LinkedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAttestation = `-- name: GetAttestation :one
+SELECT day, root, leaf_count, first_chain_seq, last_chain_seq, created_at FROM ledger_attestations WHERE day = $1
+`
+
+func (q *Queries) GetAttestation(ctx context.Context, day pgtype.Date) (LedgerAttestation, error) {
+	row := q.db.QueryRow(ctx, getAttestation, day)
+	var i LedgerAttestation
+	err := row.Scan(
+		&i.Day,
+		&i.Root,
+		&i.LeafCount,
+		&i.FirstChainSeq,
+		&i.LastChainSeq,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAttestationByChainSeq = `-- name: GetAttestationByChainSeq :one
+SELECT day, root, leaf_count, first_chain_seq, last_chain_seq, created_at FROM ledger_attestations
+WHERE first_chain_seq <= $1::BIGINT AND last_chain_seq >= $1::BIGINT
+`
+
+func (q *Queries) GetAttestationByChainSeq(ctx context.Context, chainSeq int64) (LedgerAttestation, error) {
+	row := q.db.QueryRow(ctx, getAttestationByChainSeq, chainSeq)
+	var i LedgerAttestation
+	err := row.Scan(
+		&i.Day,
+		&i.Root,
+		&i.LeafCount,
+		&i.FirstChainSeq,
+		&i.LastChainSeq,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getBalanceDrift = `-- name: GetBalanceDrift :many
+SELECT accounts.id AS account_id,
+       accounts.currency,
+       COALESCE(account_balances.balance, 0)::BIGINT AS materialized,
+       COALESCE(sums.total, 0)::BIGINT AS computed
+FROM accounts
+LEFT JOIN account_balances ON account_balances.account_id = accounts.id
+LEFT JOIN (
+    SELECT account_id, SUM(amount) AS total FROM entries GROUP BY account_id
+) sums ON sums.account_id = accounts.id
+WHERE COALESCE(account_balances.balance, 0) <> COALESCE(sums.total, 0)
+`
+
+type GetBalanceDriftRow struct {
+	AccountID    uuid.UUID
+	Currency     Currency
+	Materialized int64
+	Computed     int64
+}
+
+func (q *Queries) GetBalanceDrift(ctx context.Context) ([]GetBalanceDriftRow, error) {
+	rows, err := q.db.Query(ctx, getBalanceDrift)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBalanceDriftRow
+	for rows.Next() {
+		var i GetBalanceDriftRow
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Currency,
+			&i.Materialized,
+			&i.Computed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDuplicateExternalIDs = `-- name: GetDuplicateExternalIDs :many
+SELECT external_id::TEXT AS external_id, array_agg(id ORDER BY created_at, id)::UUID[] AS transaction_ids
+FROM transactions
+WHERE external_id IS NOT NULL
+GROUP BY external_id
+HAVING COUNT(*) > 1
+ORDER BY external_id
+`
+
+type GetDuplicateExternalIDsRow struct {
+	ExternalID     string
+	TransactionIds []uuid.UUID
+}
+
+func (q *Queries) GetDuplicateExternalIDs(ctx context.Context) ([]GetDuplicateExternalIDsRow, error) {
+	rows, err := q.db.Query(ctx, getDuplicateExternalIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDuplicateExternalIDsRow
+	for rows.Next() {
+		var i GetDuplicateExternalIDsRow
+		if err := rows.Scan(&i.ExternalID, &i.TransactionIds); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEntriesByTransactionID = `-- name: GetEntriesByTransactionID :many
+SELECT id, transaction_id, account_id, amount::BIGINT AS amount, currency, metadata, created_at, effective_at
+FROM entries
+WHERE transaction_id = $1
+ORDER BY amount, id
+`
+
+type GetEntriesByTransactionIDRow struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	AccountID     uuid.UUID
+	Amount        int64
+	Currency      Currency
+	Metadata      []byte
+	CreatedAt     pgtype.Timestamptz
+	EffectiveAt   pgtype.Timestamptz
+}
+
+func (q *Queries) GetEntriesByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]GetEntriesByTransactionIDRow, error) {
+	rows, err := q.db.Query(ctx, getEntriesByTransactionID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEntriesByTransactionIDRow
+	for rows.Next() {
+		var i GetEntriesByTransactionIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransactionID,
+			&i.AccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.EffectiveAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEntriesByTransactionIDs = `-- name: GetEntriesByTransactionIDs :many
+SELECT id, transaction_id, account_id, TRIM_SCALE(amount)::TEXT AS amount, currency, metadata, created_at, effective_at
+FROM entries
+WHERE transaction_id = ANY($1::UUID[])
+ORDER BY transaction_id, id
+`
+
+type GetEntriesByTransactionIDsRow struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	AccountID     uuid.UUID
+	Amount        string
+	Currency      Currency
+	Metadata      []byte
+	CreatedAt     pgtype.Timestamptz
+	EffectiveAt   pgtype.Timestamptz
+}
+
+func (q *Queries) GetEntriesByTransactionIDs(ctx context.Context, transactionIds []uuid.UUID) ([]GetEntriesByTransactionIDsRow, error) {
+	rows, err := q.db.Query(ctx, getEntriesByTransactionIDs, transactionIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEntriesByTransactionIDsRow
+	for rows.Next() {
+		var i GetEntriesByTransactionIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransactionID,
+			&i.AccountID,
+			&i.Amount,
+			&i.Currency,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.EffectiveAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEntryCurrencyMismatches = `-- name: GetEntryCurrencyMismatches :many
+SELECT entries.id AS entry_id,
+       entries.transaction_id,
+       entries.account_id,
+       entries.currency AS entry_currency,
+       accounts.currency AS account_currency
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+WHERE entries.currency <> accounts.currency
+ORDER BY entries.seq
+`
+
+type GetEntryCurrencyMismatchesRow struct {
+	EntryID         uuid.UUID
+	TransactionID   uuid.UUID
+	AccountID       uuid.UUID
+	EntryCurrency   Currency
+	AccountCurrency Currency
+}
+
+func (q *Queries) GetEntryCurrencyMismatches(ctx context.Context) ([]GetEntryCurrencyMismatchesRow, error) {
+	rows, err := q.db.Query(ctx, getEntryCurrencyMismatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEntryCurrencyMismatchesRow
+	for rows.Next() {
+		var i GetEntryCurrencyMismatchesRow
+		if err := rows.Scan(
+			&i.EntryID,
+			&i.TransactionID,
+			&i.AccountID,
+			&i.EntryCurrency,
+			&i.AccountCurrency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredHoldIDs = `-- name: GetExpiredHoldIDs :many
+SELECT id FROM holds
+WHERE status = 'pending' AND expires_at <= NOW()
+ORDER BY expires_at
+LIMIT $1
+`
+
+func (q *Queries) GetExpiredHoldIDs(ctx context.Context, limit int32) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getExpiredHoldIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getForeignDailyFlows = `-- name: GetForeignDailyFlows :many
+SELECT accounts.id AS account_id,
+       accounts.name,
+       accounts.currency,
+       accounts.account_class,
+       accounts.normal_balance,
+       (entries.effective_at AT TIME ZONE 'UTC')::DATE AS day,
+       SUM(entries.amount)::BIGINT AS amount
+FROM accounts
+JOIN entries ON entries.account_id = accounts.id
+WHERE accounts.currency <> $1
+  AND accounts.account_class IN ('asset', 'liability')
+  AND entries.effective_at <= $2
+GROUP BY accounts.id, day
+ORDER BY accounts.currency, accounts.account_class, accounts.name, accounts.id, day
+`
+
+type GetForeignDailyFlowsParams struct {
+	ReportingCurrency Currency
+	AsOf              pgtype.Timestamptz
+}
+
+type GetForeignDailyFlowsRow struct {
+	AccountID     uuid.UUID
+	Name          string
+	Currency      Currency
+	AccountClass  AccountClass
+	NormalBalance string
+	Day           pgtype.Date
+	Amount        int64
+}
+
+func (q *Queries) GetForeignDailyFlows(ctx context.Context, arg GetForeignDailyFlowsParams) ([]GetForeignDailyFlowsRow, error) {
+	rows, err := q.db.Query(ctx, getForeignDailyFlows, arg.ReportingCurrency, arg.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetForeignDailyFlowsRow
+	for rows.Next() {
+		var i GetForeignDailyFlowsRow
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Name,
+			&i.Currency,
+			&i.AccountClass,
+			&i.NormalBalance,
+			&i.Day,
+			&i.Amount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFxQuote = `-- name: GetFxQuote :one
+SELECT id, source_currency, target_currency, source_amount, target_amount, mid_rate, spread_bps, rate, rate_source, expires_at, transaction_id, used_at, created_at FROM fx_quotes WHERE id = $1
+`
+
+func (q *Queries) GetFxQuote(ctx context.Context, id uuid.UUID) (FxQuote, error) {
+	row := q.db.QueryRow(ctx, getFxQuote, id)
+	var i FxQuote
+	err := row.Scan(
+		&i.ID,
+		&i.SourceCurrency,
+		&i.TargetCurrency,
+		&i.SourceAmount,
+		&i.TargetAmount,
+		&i.MidRate,
+		&i.SpreadBps,
+		&i.Rate,
+		&i.RateSource,
+		&i.ExpiresAt,
+		&i.TransactionID,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getFxRateAt = `-- name: GetFxRateAt :one
+SELECT id, base, quote, rate, source, as_of, created_at FROM fx_rates
+WHERE ((base = $1 AND quote = $2) OR (base = $2 AND quote = $1))
+  AND as_of <= $3
+ORDER BY as_of DESC, created_at DESC
+LIMIT 1
+`
+
+type GetFxRateAtParams struct {
+	Base  Currency
+	Quote Currency
+	At    pgtype.Timestamptz
+}
+
+func (q *Queries) GetFxRateAt(ctx context.Context, arg GetFxRateAtParams) (FxRate, error) {
+	row := q.db.QueryRow(ctx, getFxRateAt, arg.Base, arg.Quote, arg.At)
+	var i FxRate
+	err := row.Scan(
+		&i.ID,
+		&i.Base,
+		&i.Quote,
+		&i.Rate,
+		&i.Source,
+		&i.AsOf,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getFxRateHistory = `-- name: GetFxRateHistory :many
+SELECT id, base, quote, rate, source, as_of, created_at FROM fx_rates
+WHERE ((base = $1 AND quote = $2) OR (base = $2 AND quote = $1))
+  AND as_of <= $3
+ORDER BY as_of, created_at
+`
+
+type GetFxRateHistoryParams struct {
+	Base  Currency
+	Quote Currency
+	At    pgtype.Timestamptz
+}
+
+func (q *Queries) GetFxRateHistory(ctx context.Context, arg GetFxRateHistoryParams) ([]FxRate, error) {
+	rows, err := q.db.Query(ctx, getFxRateHistory, arg.Base, arg.Quote, arg.At)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FxRate
+	for rows.Next() {
+		var i FxRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.Base,
+			&i.Quote,
+			&i.Rate,
+			&i.Source,
+			&i.AsOf,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHashChain = `-- name: GetHashChain :many
+SELECT id, external_id, description, status, created_by, created_at, reverses_transaction_id, metadata, hash, prev_hash, chain_seq, linked_at FROM transactions
+WHERE chain_seq > $1::BIGINT
+ORDER BY chain_seq
+LIMIT $2
+`
+
+type GetHashChainParams struct {
+	AfterSeq int64
+	PageSize int32
+}
+
+func (q *Queries) GetHashChain(ctx context.Context, arg GetHashChainParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, getHashChain, arg.AfterSeq, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.Description,
+			&i.Status,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.ReversesTransactionID,
+			&i.Metadata,
+			&i.Hash,
+			&i.PrevHash,
+			&i.ChainSeq,
+			&i.LinkedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHashChainHead = `-- name: GetHashChainHead :one
+SELECT chain_seq::BIGINT AS chain_seq, hash
+FROM transactions
+WHERE chain_seq IS NOT NULL
+ORDER BY chain_seq DESC
+LIMIT 1
+`
+
+type GetHashChainHeadRow struct {
+	ChainSeq int64
+	Hash     []byte
+}
+
+func (q *Queries) GetHashChainHead(ctx context.Context) (GetHashChainHeadRow, error) {
+	row := q.db.QueryRow(ctx, getHashChainHead)
+	var i GetHashChainHeadRow
+	err := row.Scan(&i.ChainSeq, &i.Hash)
+	return i, err
+}
+
+const getHold = `-- name: GetHold :one
+SELECT id, transaction_id, account_id, counterparty_id, currency, amount, captured_amount, status, expires_at, created_at, updated_at FROM holds WHERE id = $1
+`
+
+func (q *Queries) GetHold(ctx context.Context, id uuid.UUID) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHold, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.CounterpartyID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getHoldByTransactionID = `-- name: GetHoldByTransactionID :one
+SELECT id, transaction_id, account_id, counterparty_id, currency, amount, captured_amount, status, expires_at, created_at, updated_at FROM holds WHERE transaction_id = $1
+`
+
+func (q *Queries) GetHoldByTransactionID(ctx context.Context, transactionID uuid.UUID) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHoldByTransactionID, transactionID)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.CounterpartyID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getHoldForUpdate = `-- name: GetHoldForUpdate :one
+SELECT id, transaction_id, account_id, counterparty_id, currency, amount, captured_amount, status, expires_at, created_at, updated_at FROM holds WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetHoldForUpdate(ctx context.Context, id uuid.UUID) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHoldForUpdate, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.CounterpartyID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getLastAttestedChainSeq = `-- name: GetLastAttestedChainSeq :one
+SELECT COALESCE(MAX(last_chain_seq), 0)::BIGINT AS chain_seq FROM ledger_attestations
+`
+
+func (q *Queries) GetLastAttestedChainSeq(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getLastAttestedChainSeq)
+	var chain_seq int64
+	err := row.Scan(&chain_seq)
+	return chain_seq, err
+}
+
+const getLastChainSeqBefore = `-- name: GetLastChainSeqBefore :one
+SELECT COALESCE(MAX(chain_seq), 0)::BIGINT AS chain_seq
+FROM transactions
+WHERE chain_seq > $1::BIGINT AND linked_at < $2
+`
+
+type GetLastChainSeqBeforeParams struct {
+	AfterSeq   int64
+	BeforeTime pgtype.Timestamptz
+}
+
+func (q *Queries) GetLastChainSeqBefore(ctx context.Context, arg GetLastChainSeqBeforeParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getLastChainSeqBefore, arg.AfterSeq, arg.BeforeTime)
+	var chain_seq int64
+	err := row.Scan(&chain_seq)
+	return chain_seq, err
+}
+
+const getLinkedTransactions = `-- name: GetLinkedTransactions :many
+SELECT id, external_id, description, status, created_by, created_at, reverses_transaction_id, metadata, hash, prev_hash, chain_seq, linked_at FROM transactions
+WHERE chain_seq >= $1::BIGINT AND chain_seq <= $2::BIGINT
+ORDER BY chain_seq
+`
+
+type GetLinkedTransactionsParams struct {
+	FromSeq int64
+	ToSeq   int64
+}
+
+func (q *Queries) GetLinkedTransactions(ctx context.Context, arg GetLinkedTransactionsParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, getLinkedTransactions, arg.FromSeq, arg.ToSeq)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.Description,
+			&i.Status,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.ReversesTransactionID,
+			&i.Metadata,
+			&i.Hash,
+			&i.PrevHash,
+			&i.ChainSeq,
+			&i.LinkedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrphanTransactions = `-- name: GetOrphanTransactions :many
+SELECT id, external_id, status, created_at
+FROM transactions
+WHERE status NOT IN ('pending', 'voided')
+  AND NOT EXISTS (SELECT 1 FROM entries WHERE entries.transaction_id = transactions.id)
+ORDER BY created_at, id
+`
+
+type GetOrphanTransactionsRow struct {
+	ID         uuid.UUID
+	ExternalID pgtype.Text
+	Status     string
+	CreatedAt  pgtype.Timestamptz
+}
+
+func (q *Queries) GetOrphanTransactions(ctx context.Context) ([]GetOrphanTransactionsRow, error) {
+	rows, err := q.db.Query(ctx, getOrphanTransactions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrphanTransactionsRow
+	for rows.Next() {
+		var i GetOrphanTransactionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.Status,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReversedEntryAmounts = `-- name: GetReversedEntryAmounts :many
+SELECT (entries.metadata->>'reversal_of')::UUID AS entry_id,
+       SUM(entries.amount)::BIGINT AS reversed
+FROM entries
+JOIN transactions ON transactions.id = entries.transaction_id
+WHERE transactions.reverses_transaction_id = $1
+GROUP BY entries.metadata->>'reversal_of'
+`
+
+type GetReversedEntryAmountsRow struct {
+	EntryID  uuid.UUID
+	Reversed int64
+}
+
+func (q *Queries) GetReversedEntryAmounts(ctx context.Context, reversesTransactionID uuid.NullUUID) ([]GetReversedEntryAmountsRow, error) {
+	rows, err := q.db.Query(ctx, getReversedEntryAmounts, reversesTransactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReversedEntryAmountsRow
+	for rows.Next() {
+		var i GetReversedEntryAmountsRow
+		if err := rows.Scan(&i.EntryID, &i.Reversed); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSnapshotDrift = `-- name: GetSnapshotDrift :many
+SELECT latest.account_id,
+       latest.currency,
+       latest.up_to_seq,
+       latest.balance AS snapshot,
+       COALESCE((SELECT SUM(amount) FROM entries
+                 WHERE entries.account_id = latest.account_id
+                   AND entries.seq <= latest.up_to_seq), 0)::BIGINT AS computed
+FROM (
+    SELECT DISTINCT ON (account_id) account_id, currency, balance, up_to_seq
+    FROM balance_snapshots
+    ORDER BY account_id, up_to_seq DESC
+) latest
+WHERE latest.balance <> COALESCE((SELECT SUM(amount) FROM entries
+                                  WHERE entries.account_id = latest.account_id
+                                    AND entries.seq <= latest.up_to_seq), 0)
+`
+
+type GetSnapshotDriftRow struct {
+	AccountID uuid.UUID
+	Currency  Currency
+	UpToSeq   int64
+	Snapshot  int64
+	Computed  int64
+}
+
+func (q *Queries) GetSnapshotDrift(ctx context.Context) ([]GetSnapshotDriftRow, error) {
+	rows, err := q.db.Query(ctx, getSnapshotDrift)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSnapshotDriftRow
+	for rows.Next() {
+		var i GetSnapshotDriftRow
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Currency,
+			&i.UpToSeq,
+			&i.Snapshot,
+			&i.Computed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTransaction = `-- name: GetTransaction :one
+SELECT id, external_id, description, status, created_by, created_at, reverses_transaction_id, metadata, hash, prev_hash, chain_seq, linked_at FROM transactions WHERE id = $1
+`
+
+func (q *Queries) GetTransaction(ctx context.Context, id uuid.UUID) (Transaction, error) {
+	row := q.db.QueryRow(ctx, getTransaction, id)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.Description,
+		&i.Status,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ReversesTransactionID,
+		&i.Metadata,
+		&i.Hash,
+		&i.PrevHash,
+		&i.ChainSeq,
+		&i.LinkedAt,
+	)
+	return i, err
+}
+
+const getTransactionByExternalID = `-- name: GetTransactionByExternalID :one
+SELECT id, external_id, description, status, created_by, created_at, reverses_transaction_id, metadata, hash, prev_hash, chain_seq, linked_at FROM transactions WHERE external_id = $1
+`
+
+func (q *Queries) GetTransactionByExternalID(ctx context.Context, externalID pgtype.Text) (Transaction, error) {
+	row := q.db.QueryRow(ctx, getTransactionByExternalID, externalID)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.Description,
+		&i.Status,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ReversesTransactionID,
+		&i.Metadata,
+		&i.Hash,
+		&i.PrevHash,
+		&i.ChainSeq,
+		&i.LinkedAt,
+	)
+	return i, err
+}
+
+const getTransactionForUpdate = `-- name: GetTransactionForUpdate :one
+SELECT id, external_id, description, status, created_by, created_at, reverses_transaction_id, metadata, hash, prev_hash, chain_seq, linked_at FROM transactions WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetTransactionForUpdate(ctx context.Context, id uuid.UUID) (Transaction, error) {
+	row := q.db.QueryRow(ctx, getTransactionForUpdate, id)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.Description,
+		&i.Status,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ReversesTransactionID,
+		&i.Metadata,
+		&i.Hash,
+		&i.PrevHash,
+		&i.ChainSeq,
+		&i.LinkedAt,
+	)
+	return i, err
+}
+
+const getUnattestedDays = `-- name: GetUnattestedDays :many
+WITH last AS (
+    SELECT MAX(day) AS day, MAX(last_chain_seq) AS chain_seq FROM ledger_attestations
+),
+linked AS (
+    -- A link committed after its day was attested goes to the next day
+    SELECT GREATEST((transactions.linked_at AT TIME ZONE 'UTC')::DATE, last.day + 1) AS day
+    FROM transactions, last
+    WHERE transactions.chain_seq > COALESCE(last.chain_seq, 0)
+      AND transactions.linked_at < $1
+)
+SELECT DISTINCT day::DATE AS day
+FROM linked
+WHERE day < ($1::TIMESTAMPTZ AT TIME ZONE 'UTC')::DATE
+ORDER BY day
+`
+
+func (q *Queries) GetUnattestedDays(ctx context.Context, cutoff pgtype.Timestamptz) ([]pgtype.Date, error) {
+	rows, err := q.db.Query(ctx, getUnattestedDays, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.Date
+	for rows.Next() {
+		var day pgtype.Date
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		items = append(items, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnbalancedTransactions = `-- name: GetUnbalancedTransactions :many
+SELECT transaction_id, currency, SUM(amount)::BIGINT AS total
+FROM entries
+GROUP BY transaction_id, currency
+HAVING SUM(amount) <> 0
+ORDER BY transaction_id, currency
+`
+
+type GetUnbalancedTransactionsRow struct {
+	TransactionID uuid.UUID
+	Currency      Currency
+	Total         int64
+}
+
+func (q *Queries) GetUnbalancedTransactions(ctx context.Context) ([]GetUnbalancedTransactionsRow, error) {
+	rows, err := q.db.Query(ctx, getUnbalancedTransactions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnbalancedTransactionsRow
+	for rows.Next() {
+		var i GetUnbalancedTransactionsRow
+		if err := rows.Scan(&i.TransactionID, &i.Currency, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnchainedTransactionIDs = `-- name: GetUnchainedTransactionIDs :many
+SELECT id FROM transactions
+WHERE chain_seq IS NULL AND status NOT IN ('pending', 'voided')
+ORDER BY created_at, id
+LIMIT $1
+`
+
+func (q *Queries) GetUnchainedTransactionIDs(ctx context.Context, limit int32) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getUnchainedTransactionIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const linkTransaction = `-- name: LinkTransaction :exec
+UPDATE transactions
+SET hash = $1, prev_hash = $2, chain_seq = $3::BIGINT, linked_at = clock_timestamp()
+WHERE id = $4
+`
+
+type LinkTransactionParams struct {
+	Hash     []byte
+	PrevHash []byte
+	ChainSeq int64
+	ID       uuid.UUID
+}
+
+func (q *Queries) LinkTransaction(ctx context.Context, arg LinkTransactionParams) error {
+	_, err := q.db.Exec(ctx, linkTransaction,
+		arg.Hash,
+		arg.PrevHash,
+		arg.ChainSeq,
+		arg.ID,
+	)
+	return err
+}
+
+const lockAccountTree = `-- name: LockAccountTree :exec
+SELECT pg_advisory_xact_lock(hashtext('accounts_tree'))
+`
+
+func (q *Queries) LockAccountTree(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockAccountTree)
+	return err
+}
+
+const lockHashChain = `-- name: LockHashChain :exec
+SELECT pg_advisory_xact_lock(hashtext('transactions_hash_chain'))
+`
+
+func (q *Queries) LockHashChain(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockHashChain)
+	return err
+}
+
+const postPendingTransaction = `-- name: PostPendingTransaction :one
+UPDATE transactions SET status = 'posted'
+WHERE id = $1 AND status = 'pending'
+RETURNING id, external_id, description, status, created_by, created_at, reverses_transaction_id, metadata, hash, prev_hash, chain_seq, linked_at
+`
+
+func (q *Queries) PostPendingTransaction(ctx context.Context, id uuid.UUID) (Transaction, error) {
+	row := q.db.QueryRow(ctx, postPendingTransaction, id)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.Description,
+		&i.Status,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ReversesTransactionID,
+		&i.Metadata,
+		&i.Hash,
+		&i.PrevHash,
+		&i.ChainSeq,
+		&i.LinkedAt,
+	)
+	return i, err
+}
+
+const searchTransactions = `-- name: SearchTransactions :many
+SELECT id, external_id, description, status, created_by, created_at, reverses_transaction_id, metadata, hash, prev_hash, chain_seq, linked_at FROM transactions
+WHERE ($1::TEXT IS NULL OR status = $1)
+  AND ($2::TEXT IS NULL OR external_id = $2)
+  AND ($3::TEXT IS NULL OR created_by = $3)
+  AND ($4::TIMESTAMPTZ IS NULL OR created_at >= $4)
+  AND ($5::TIMESTAMPTZ IS NULL OR created_at < $5)
+  AND ($6::UUID IS NULL OR EXISTS (
+        SELECT 1 FROM entries
+        WHERE entries.transaction_id = transactions.id
+          AND entries.account_id = $6))
+  AND ($7::JSONB IS NULL OR metadata @> $7)
+  AND ($8::TIMESTAMPTZ IS NULL
+       OR (created_at, id) < ($8, $9::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT $10
+`
+
+type SearchTransactionsParams struct {
+	Status          pgtype.Text
+	ExternalID      pgtype.Text
+	CreatedBy       pgtype.Text
+	FromDate        pgtype.Timestamptz
+	ToDate          pgtype.Timestamptz
+	AccountID       uuid.NullUUID
+	Metadata        []byte
+	CursorCreatedAt pgtype.Timestamptz
+	CursorID        uuid.NullUUID
+	PageSize        int32
+}
+
+func (q *Queries) SearchTransactions(ctx context.Context, arg SearchTransactionsParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, searchTransactions,
+		arg.Status,
+		arg.ExternalID,
+		arg.CreatedBy,
+		arg.FromDate,
+		arg.ToDate,
+		arg.AccountID,
+		arg.Metadata,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.Description,
+			&i.Status,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.ReversesTransactionID,
+			&i.Metadata,
+			&i.Hash,
+			&i.PrevHash,
+			&i.ChainSeq,
+			&i.LinkedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAccountBalancePolicy = `-- name: UpdateAccountBalancePolicy :one
+UPDATE accounts SET balance_policy = $2, overdraft_limit = $3
+WHERE id = $1
+RETURNING id, name, currency, metadata, created_at, status, closed_at, balance_policy, overdraft_limit, account_class, normal_balance, parent_id
+`
+
+type UpdateAccountBalancePolicyParams struct {
+	ID             uuid.UUID
+	BalancePolicy  string
+	OverdraftLimit int64
+}
+
+func (q *Queries) UpdateAccountBalancePolicy(ctx context.Context, arg UpdateAccountBalancePolicyParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountBalancePolicy, arg.ID, arg.BalancePolicy, arg.OverdraftLimit)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.BalancePolicy,
+		&i.OverdraftLimit,
+		&i.AccountClass,
+		&i.NormalBalance,
+		&i.ParentID,
+	)
+	return i, err
+}
+
+const updateAccountMetadata = `-- name: UpdateAccountMetadata :one
+UPDATE accounts
+SET metadata = COALESCE(metadata, '{}'::jsonb) || $1::jsonb
+WHERE id = $2
+RETURNING id, name, currency, metadata, created_at, status, closed_at, balance_policy, overdraft_limit, account_class, normal_balance, parent_id
+`
+
+type UpdateAccountMetadataParams struct {
+	Metadata []byte
+	ID       uuid.UUID
+}
+
+func (q *Queries) UpdateAccountMetadata(ctx context.Context, arg UpdateAccountMetadataParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountMetadata, arg.Metadata, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.BalancePolicy,
+		&i.OverdraftLimit,
+		&i.AccountClass,
+		&i.NormalBalance,
+		&i.ParentID,
+	)
+	return i, err
+}
+
+const updateAccountParent = `-- name: UpdateAccountParent :one
+UPDATE accounts SET parent_id = $2
+WHERE id = $1
+RETURNING id, name, currency, metadata, created_at, status, closed_at, balance_policy, overdraft_limit, account_class, normal_balance, parent_id
+`
+
+type UpdateAccountParentParams struct {
+	ID       uuid.UUID
+	ParentID uuid.NullUUID
+}
+
+func (q *Queries) UpdateAccountParent(ctx context.Context, arg UpdateAccountParentParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountParent, arg.ID, arg.ParentID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.BalancePolicy,
+		&i.OverdraftLimit,
+		&i.AccountClass,
+		&i.NormalBalance,
+		&i.ParentID,
+	)
+	return i, err
+}
+
+const updateHoldStatus = `-- name: UpdateHoldStatus :one
+UPDATE holds SET status = $2, captured_amount = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, transaction_id, account_id, counterparty_id, currency, amount, captured_amount, status, expires_at, created_at, updated_at
+`
+
+type UpdateHoldStatusParams struct {
+	ID             uuid.UUID
+	Status         string
+	CapturedAmount int64
+}
+
+func (q *Queries) UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) (Hold, error) {
+	row := q.db.QueryRow(ctx, updateHoldStatus, arg.ID, arg.Status, arg.CapturedAmount)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.CounterpartyID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateTransactionStatus = `-- name: UpdateTransactionStatus :exec
+UPDATE transactions SET status = $2 WHERE id = $1
+`
+
+type UpdateTransactionStatusParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+func (q *Queries) UpdateTransactionStatus(ctx context.Context, arg UpdateTransactionStatusParams) error {
+	_, err := q.db.Exec(ctx, updateTransactionStatus, arg.ID, arg.Status)
+	return err
+}
+
+const useFxQuote = `-- name: UseFxQuote :one
+UPDATE fx_quotes SET transaction_id = $2, used_at = NOW()
+WHERE id = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING id, source_currency, target_currency, source_amount, target_amount, mid_rate, spread_bps, rate, rate_source, expires_at, transaction_id, used_at, created_at
+`
+
+type UseFxQuoteParams struct {
+	ID            uuid.UUID
+	TransactionID uuid.NullUUID
+}
+
+func (q *Queries) UseFxQuote(ctx context.Context, arg UseFxQuoteParams) (FxQuote, error) {
+	row := q.db.QueryRow(ctx, useFxQuote, arg.ID, arg.TransactionID)
+	var i FxQuote
+	err := row.Scan(
+		&i.ID,
+		&i.SourceCurrency,
+		&i.TargetCurrency,
+		&i.SourceAmount,
+		&i.TargetAmount,
+		&i.MidRate,
+		&i.SpreadBps,
+		&i.Rate,
+		&i.RateSource,
+		&i.ExpiresAt,
+		&i.TransactionID,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
 }